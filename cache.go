@@ -0,0 +1,220 @@
+/*
+ * cache.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"path"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for cache of resources shared between maps.
+// Tileset pictures are loaded only once for each resolved
+// file path and kept in cache as long as there is at least
+// one reference to them. Only pictures are cached, tileset
+// data is parsed from each map file. Cache is safe for
+// concurrent use.
+type ResourceCache struct {
+	mutex    sync.Mutex
+	pictures map[cacheKey]*cachedPicture
+}
+
+// Struct for resource cache key. Files with the same path
+// in different file systems, or loaded with different image
+// loaders, are cached separately.
+type cacheKey struct {
+	fsys   any
+	loader any
+	path   string
+}
+
+// Struct for cached picture.
+type cachedPicture struct {
+	key     cacheKey
+	picture pixel.Picture
+	err     error
+	refs    int
+	loaded  chan struct{}
+}
+
+// NewResourceCache creates new empty resource cache.
+func NewResourceCache() *ResourceCache {
+	c := new(ResourceCache)
+	c.pictures = make(map[cacheKey]*cachedPicture)
+	return c
+}
+
+// Release releases single reference to picture from file
+// with specified path, from OS file system or file system
+// specified on map creation, loaded with any image loader.
+// Picture is removed from the cache after releasing the last
+// reference.
+func (c *ResourceCache) Release(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, cp := range c.entries(path) {
+		c.releaseEntry(cp)
+	}
+}
+
+// Invalidate removes picture from file with specified path,
+// from OS file system or file system specified on map creation,
+// loaded with any image loader, from the cache, so the picture will be loaded again from
+// the file on the next use, e.g. after the file was changed.
+// Maps created before invalidation keep using old picture.
+func (c *ResourceCache) Invalidate(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, cp := range c.entries(path) {
+		delete(c.pictures, cp.key)
+	}
+}
+
+// References returns number of references to picture from
+// file with specified path, from OS file system or file system
+// specified on map creation, loaded with any image loader.
+func (c *ResourceCache) References(path string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	refs := 0
+	for _, cp := range c.entries(path) {
+		refs += cp.refs
+	}
+	return refs
+}
+
+// Len returns number of pictures in the cache.
+func (c *ResourceCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.pictures)
+}
+
+// Clear removes all pictures from the cache, regardless
+// of references.
+func (c *ResourceCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pictures = make(map[cacheKey]*cachedPicture)
+}
+
+// picture returns cache entry with picture from file with
+// specified key, from cache or loaded from specified path with
+// specified function if there is no such picture in the cache
+// yet. Each call adds a reference to the entry that should be
+// released after use.
+// Picture is decoded without locking the cache, so many
+// pictures can be loaded concurrently.
+func (c *ResourceCache) picture(key cacheKey, path string, load func(path string) (pixel.Picture, error)) (*cachedPicture, error) {
+	c.mutex.Lock()
+	cp := c.pictures[key]
+	if cp != nil {
+		cp.refs++
+		c.mutex.Unlock()
+		<-cp.loaded
+		return cp, cp.err
+	}
+	cp = &cachedPicture{key: key, refs: 1, loaded: make(chan struct{})}
+	c.pictures[key] = cp
+	c.mutex.Unlock()
+	cp.picture, cp.err = load(path)
+	if cp.err != nil {
+		c.mutex.Lock()
+		if c.pictures[key] == cp {
+			delete(c.pictures, key)
+		}
+		c.mutex.Unlock()
+	}
+	close(cp.loaded)
+	return cp, cp.err
+}
+
+// invalidate removes entry with specified key from the cache.
+func (c *ResourceCache) invalidate(key cacheKey) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.pictures, key)
+}
+
+// release releases single reference to specified cache
+// entry.
+func (c *ResourceCache) release(cp *cachedPicture) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.releaseEntry(cp)
+}
+
+// releaseEntry releases single reference to specified cache
+// entry. Entry is removed from the cache after releasing
+// the last reference. Cache must be locked by the caller.
+func (c *ResourceCache) releaseEntry(cp *cachedPicture) {
+	cp.refs--
+	if cp.refs < 1 && c.pictures[cp.key] == cp {
+		delete(c.pictures, cp.key)
+	}
+}
+
+// entries returns all cache entries for file with specified
+// path, from any file system and loaded with any image loader.
+// Cache must be locked by the caller.
+func (c *ResourceCache) entries(path string) []*cachedPicture {
+	var entries []*cachedPicture
+	for key, cp := range c.pictures {
+		if key == newCacheKey(key.fsys, key.loader, path) {
+			entries = append(entries, cp)
+		}
+	}
+	return entries
+}
+
+// newCacheKey returns cache key for file with specified path
+// in file system with specified identifier, loaded with image
+// loader with specified identifier. Paths in OS file system
+// are resolved to absolute paths, paths in other file systems
+// are only cleaned, as they are not OS paths.
+func newCacheKey(fsys, loader any, filePath string) cacheKey {
+	if fsys != nil {
+		return cacheKey{fsys, loader, path.Clean(filePath)}
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return cacheKey{nil, loader, filepath.Clean(filePath)}
+	}
+	return cacheKey{nil, loader, abs}
+}
+
+// resourceID returns identifier of specified file system
+// or image loader, for resource cache keys. Comparable values
+// identify themselves, other values, like maps or functions,
+// get a new unique identifier, so resources loaded with them
+// are shared only between maps created with the same option.
+func resourceID(v any) any {
+	if v == nil || reflect.ValueOf(v).Comparable() {
+		return v
+	}
+	return new(byte)
+}
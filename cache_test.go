@@ -0,0 +1,183 @@
+/*
+ * cache_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// testFS returns file system with test map grid.
+func testFS(t *testing.T) fstest.MapFS {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 128, 32))
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fstest.MapFS{
+		"res/map.tmx":   {Data: []byte(gridTMX("..", ".#"))},
+		"res/tiles.png": {Data: buf.Bytes()},
+	}
+}
+
+func TestResourceCacheShared(t *testing.T) {
+	cache := NewResourceCache()
+	path := writeTestMap(t, gridTMX("..", ".#"))
+	m1, err := NewMap(path, WithResourceCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := NewMap(path, WithResourceCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgPath := filepath.Join(filepath.Dir(path), "tiles.png")
+	if cache.Len() != 1 || cache.References(imgPath) != 2 {
+		t.Fatalf("cache: pictures: %d, references: %d, expected: 1, 2",
+			cache.Len(), cache.References(imgPath))
+	}
+	if m1.Tilesets()[0].Picture() != m2.Tilesets()[0].Picture() {
+		t.Errorf("picture not shared between maps")
+	}
+	m1.Release()
+	m2.Release()
+	if cache.Len() != 0 {
+		t.Errorf("cache not empty after release: %d", cache.Len())
+	}
+}
+
+func TestResourceCacheFS(t *testing.T) {
+	cache := NewResourceCache()
+	fsys := testFS(t)
+	fsOpt := WithFS(fsys)
+	m1, err := NewMap("res/map.tmx", WithResourceCache(cache), fsOpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := NewMap("res/map.tmx", WithResourceCache(cache), fsOpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m1.Tilesets()[0].Picture() != m2.Tilesets()[0].Picture() {
+		t.Errorf("picture not shared between maps from the same file system")
+	}
+	// The same path in other file system.
+	_, err = NewMap("res/map.tmx", WithResourceCache(cache),
+		WithFS(testFS(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("cache pictures: %d, expected: 2", cache.Len())
+	}
+	// Paths in file systems are not OS paths.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range cache.pictures {
+		if key.path != "res/tiles.png" {
+			t.Errorf("cache path: %s, expected: res/tiles.png", key.path)
+		}
+	}
+	if cache.References(filepath.Join(wd, "res/tiles.png")) != 0 {
+		t.Errorf("file system picture referenced by OS path")
+	}
+	if cache.References("res/tiles.png") != 3 {
+		t.Errorf("references: %d, expected: 3",
+			cache.References("res/tiles.png"))
+	}
+	cache.Invalidate("res/tiles.png")
+	if cache.Len() != 0 {
+		t.Errorf("cache not empty after invalidation: %d", cache.Len())
+	}
+}
+
+func TestResourceIDComparable(t *testing.T) {
+	fsys := os.DirFS("testdata")
+	if resourceID(fsys) != resourceID(os.DirFS("testdata")) {
+		t.Errorf("different identifiers of comparable file systems")
+	}
+	mapFS := fstest.MapFS{}
+	if resourceID(mapFS) == resourceID(mapFS) {
+		t.Errorf("the same identifier for non-comparable values")
+	}
+	if resourceID(nil) != nil {
+		t.Errorf("identifier of nil value is not nil")
+	}
+}
+
+func TestResourceCacheLoader(t *testing.T) {
+	cache := NewResourceCache()
+	path := writeTestMap(t, gridTMX("..", ".#"))
+	loaded := 0
+	loaderOpt := WithImageLoader(ImageLoaderFunc(func(path string, r io.Reader) (image.Image, error) {
+		loaded++
+		return image.NewRGBA(image.Rect(0, 0, 128, 32)), nil
+	}))
+	m1, err := NewMap(path, WithResourceCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := NewMap(path, WithResourceCache(cache), loaderOpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m3, err := NewMap(path, WithResourceCache(cache), loaderOpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != 1 {
+		t.Errorf("custom loader calls: %d, expected: 1", loaded)
+	}
+	if m1.Tilesets()[0].Picture() == m2.Tilesets()[0].Picture() {
+		t.Errorf("picture shared between maps with different loaders")
+	}
+	if m2.Tilesets()[0].Picture() != m3.Tilesets()[0].Picture() {
+		t.Errorf("picture not shared between maps with the same loader")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("cache pictures: %d, expected: 2", cache.Len())
+	}
+}
+
+func TestWithImageDecoderNil(t *testing.T) {
+	path := writeTestMap(t, gridTMX("..", ".#"))
+	_, err := NewMap(path, WithImageDecoder(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewMap(path, WithImageLoader(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
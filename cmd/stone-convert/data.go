@@ -0,0 +1,164 @@
+/*
+ * data.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// decodeCSV decodes tile GIDs from specified CSV data.
+func decodeCSV(data string) ([]uint32, error) {
+	gids := make([]uint32, 0)
+	for _, v := range strings.Split(data, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) < 1 {
+			continue
+		}
+		gid, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GID: %s", v)
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids, nil
+}
+
+// encodeCSV encodes specified tile GIDs to CSV data, with
+// one row of tiles in each line, as in Tiled.
+func encodeCSV(gids []uint32, width int) string {
+	b := new(strings.Builder)
+	for i, gid := range gids {
+		if width > 0 && i%width == 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(strconv.FormatUint(uint64(gid), 10))
+		if i < len(gids)-1 {
+			b.WriteString(",")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// decodeBase64 decodes tile GIDs from specified base64 data
+// compressed with specified compression method.
+func decodeBase64(data, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode base64: %v", err)
+	}
+	var r io.Reader = bytes.NewReader(raw)
+	switch compression {
+	case "":
+	case "gzip":
+		r, err = gzip.NewReader(r)
+	case "zlib":
+		r, err = zlib.NewReader(r)
+	case "zstd":
+		var dec *zstd.Decoder
+		dec, err = zstd.NewReader(r)
+		if err == nil {
+			defer dec.Close()
+			r = dec
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", compression)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress data: %v", err)
+	}
+	raw, err = io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress data: %v", err)
+	}
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("invalid data length: %d", len(raw))
+	}
+	gids := make([]uint32, len(raw)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return gids, nil
+}
+
+// encodeBase64 encodes specified tile GIDs to base64 data
+// compressed with specified compression method.
+func encodeBase64(gids []uint32, compression string) (string, error) {
+	raw := make([]byte, len(gids)*4)
+	for i, gid := range gids {
+		binary.LittleEndian.PutUint32(raw[i*4:], gid)
+	}
+	buf := new(bytes.Buffer)
+	var w io.WriteCloser
+	switch compression {
+	case "":
+		return base64.StdEncoding.EncodeToString(raw), nil
+	case "gzip":
+		w = gzip.NewWriter(buf)
+	case "zlib":
+		w = zlib.NewWriter(buf)
+	case "zstd":
+		enc, err := zstd.NewWriter(buf)
+		if err != nil {
+			return "", fmt.Errorf("unable to create encoder: %v", err)
+		}
+		w = enc
+	default:
+		return "", fmt.Errorf("unsupported compression: %s", compression)
+	}
+	_, err := w.Write(raw)
+	if err != nil {
+		return "", fmt.Errorf("unable to compress data: %v", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return "", fmt.Errorf("unable to compress data: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeData decodes tile GIDs from specified layer data.
+func decodeData(data, encoding, compression string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		if len(compression) > 0 {
+			return nil, fmt.Errorf("compression is not supported for CSV data")
+		}
+		return decodeCSV(data)
+	case "base64":
+		return decodeBase64(data, compression)
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+}
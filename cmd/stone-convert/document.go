@@ -0,0 +1,285 @@
+/*
+ * document.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"path/filepath"
+)
+
+// Layer types, as in TMJ format.
+const (
+	tileLayer   = "tilelayer"
+	objectGroup = "objectgroup"
+	imageLayer  = "imagelayer"
+	groupLayer  = "group"
+)
+
+// Struct for map document, independent from the format
+// of the map file. All file paths in document are absolute.
+type document struct {
+	Version         string
+	TiledVersion    string
+	Class           string
+	Orientation     string
+	RenderOrder     string
+	Width           int
+	Height          int
+	TileWidth       int
+	TileHeight      int
+	BackgroundColor string
+	NextLayerID     int
+	NextObjectID    int
+	Properties      []property
+	Tilesets        []*tileset
+	Layers          []*layer
+}
+
+// Struct for custom property.
+type property struct {
+	Name         string
+	Type         string
+	PropertyType string
+	Value        string
+}
+
+// Struct for tileset. Tileset with source is an external
+// tileset with no other data besides first GID.
+type tileset struct {
+	FirstGID        int
+	Source          string
+	Name            string
+	Class           string
+	TileWidth       int
+	TileHeight      int
+	Spacing         int
+	Margin          int
+	TileCount       int
+	Columns         int
+	ObjectAlignment string
+	TileOffset      *offset
+	Image           *image
+	Properties      []property
+	Tiles           []*tile
+	WangSets        []*wangSet
+}
+
+// Struct for tileset tile offset.
+type offset struct {
+	X int
+	Y int
+}
+
+// Struct for image reference.
+type image struct {
+	Source string
+	Trans  string
+	Width  int
+	Height int
+}
+
+// Struct for tileset tile data.
+type tile struct {
+	ID          int
+	Type        string
+	Probability float64
+	Properties  []property
+	Image       *image
+	ObjectGroup *layer
+	Animation   []frame
+}
+
+// Struct for tile animation frame.
+type frame struct {
+	TileID   int
+	Duration int
+}
+
+// Struct for Wang set.
+type wangSet struct {
+	Name   string
+	Type   string
+	Tile   int
+	Colors []wangColor
+	Tiles  []wangTile
+}
+
+// Struct for Wang set color.
+type wangColor struct {
+	Name        string
+	Color       string
+	Tile        int
+	Probability float64
+}
+
+// Struct for Wang set tile.
+type wangTile struct {
+	TileID int
+	WangID [8]int
+}
+
+// Struct for map layer of any type.
+type layer struct {
+	Type       string
+	ID         int
+	Name       string
+	Class      string
+	Opacity    float64
+	Visible    bool
+	Locked     bool
+	OffsetX    float64
+	OffsetY    float64
+	ParallaxX  float64
+	ParallaxY  float64
+	TintColor  string
+	Properties []property
+	// Tile layer.
+	Width       int
+	Height      int
+	Encoding    string
+	Compression string
+	Data        []uint32
+	// Object group.
+	Color     string
+	DrawOrder string
+	Objects   []*object
+	// Image layer.
+	Image   *image
+	RepeatX bool
+	RepeatY bool
+	// Group layer.
+	Layers []*layer
+}
+
+// Struct for map object.
+type object struct {
+	ID         int
+	Name       string
+	Type       string
+	X          float64
+	Y          float64
+	Width      float64
+	Height     float64
+	Rotation   float64
+	GID        uint32
+	Visible    bool
+	Template   string
+	Ellipse    bool
+	Point      bool
+	Polygon    []point
+	Polyline   []point
+	Text       *text
+	Properties []property
+}
+
+// Struct for polygon point.
+type point struct {
+	X float64
+	Y float64
+}
+
+// Struct for text object data.
+type text struct {
+	Text       string
+	FontFamily string
+	PixelSize  int
+	Wrap       bool
+	Color      string
+	Bold       bool
+	Italic     bool
+	Underline  bool
+	Strikeout  bool
+	Kerning    *bool
+	HAlign     string
+	VAlign     string
+}
+
+// newLayer creates new layer of specified type with
+// default attributes.
+func newLayer(layerType string) *layer {
+	return &layer{Type: layerType, Opacity: 1, Visible: true, ParallaxX: 1, ParallaxY: 1}
+}
+
+// absPath returns absolute path for specified path relative
+// to specified directory. Empty path is returned unchanged.
+func absPath(dir, path string) string {
+	if len(path) < 1 || filepath.IsAbs(filepath.FromSlash(path)) {
+		return path
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+// relPath returns path relative to specified directory for
+// specified absolute path.
+func relPath(dir, path string) string {
+	if len(path) < 1 {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// paths calls specified function for pointers to all file
+// paths in specified tileset.
+func (ts *tileset) paths(f func(path *string)) {
+	f(&ts.Source)
+	if ts.Image != nil {
+		f(&ts.Image.Source)
+	}
+	for _, t := range ts.Tiles {
+		if t.Image != nil {
+			f(&t.Image.Source)
+		}
+		if t.ObjectGroup != nil {
+			t.ObjectGroup.paths(f)
+		}
+	}
+}
+
+// paths calls specified function for pointers to all file
+// paths in specified layer and its sublayers.
+func (l *layer) paths(f func(path *string)) {
+	if l.Image != nil {
+		f(&l.Image.Source)
+	}
+	for _, o := range l.Objects {
+		f(&o.Template)
+	}
+	for _, sl := range l.Layers {
+		sl.paths(f)
+	}
+}
+
+// paths calls specified function for pointers to all file
+// paths in specified document.
+func (d *document) paths(f func(path *string)) {
+	for _, ts := range d.Tilesets {
+		ts.paths(f)
+	}
+	for _, l := range d.Layers {
+		l.paths(f)
+	}
+}
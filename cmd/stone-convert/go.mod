@@ -0,0 +1,5 @@
+module github.com/isangeles/stone/cmd/stone-convert
+
+go 1.22
+
+require github.com/klauspost/compress v1.18.0
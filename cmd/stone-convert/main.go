@@ -0,0 +1,299 @@
+/*
+ * main.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Stone-convert is command-line tool for converting maps
+// between TMX and TMJ(JSON) formats and layer data encodings.
+//
+// Usage:
+//
+//	stone-convert [flags] -o path/to/output.tmj path/to/map.tmx
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Map file formats.
+const (
+	tmxFormat = "tmx"
+	tmjFormat = "tmj"
+)
+
+// Tilesets conversion modes.
+const (
+	keepTilesets     = "keep"
+	embedTilesets    = "embed"
+	externalTilesets = "external"
+)
+
+var (
+	out         = flag.String("o", "", "output file, .tmx for TMX format, .tmj or .json for JSON format")
+	format      = flag.String("format", "", "output format: tmx or tmj, by output file extension by default")
+	encoding    = flag.String("encoding", "", "layer data encoding: csv or base64, same as in input file by default")
+	compression = flag.String("compression", "", "base64 layer data compression: gzip, zlib or zstd, no compression by default")
+	tilesets    = flag.String("tilesets", keepTilesets, "tilesets mode: keep, embed or external")
+	force       = flag.Bool("force", false, "overwrite existing tileset files in external tilesets mode")
+)
+
+// Main function.
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] -o output map\n",
+			filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || len(*out) < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	err := convert(flag.Arg(0), *out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stone-convert: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// convert converts map from file with specified path and
+// writes it to specified output file.
+func convert(path, outPath string) error {
+	outFormat, err := outputFormat(outPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read map file: %v", err)
+	}
+	var doc *document
+	if isXML(data) {
+		doc, err = readTMX(data)
+	} else {
+		doc, err = readTMJ(data)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read map: %v", err)
+	}
+	inDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("unable to resolve map directory: %v", err)
+	}
+	doc.paths(func(p *string) { *p = absPath(inDir, *p) })
+	if len(*encoding) > 0 || len(*compression) > 0 {
+		err = setEncoding(doc.Layers, *encoding, *compression)
+		if err != nil {
+			return err
+		}
+	}
+	outDir, err := filepath.Abs(filepath.Dir(outPath))
+	if err != nil {
+		return fmt.Errorf("unable to resolve output directory: %v", err)
+	}
+	switch *tilesets {
+	case keepTilesets:
+	case embedTilesets:
+		err = embed(doc)
+	case externalTilesets:
+		err = externalize(doc, outDir, outFormat)
+	default:
+		err = fmt.Errorf("invalid tilesets mode: %s", *tilesets)
+	}
+	if err != nil {
+		return err
+	}
+	doc.paths(func(p *string) { *p = relPath(outDir, *p) })
+	if outFormat == tmxFormat {
+		data, err = writeTMX(doc)
+	} else {
+		data, err = writeTMJ(doc)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write map: %v", err)
+	}
+	err = os.WriteFile(outPath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write map file: %v", err)
+	}
+	return nil
+}
+
+// outputFormat returns output format for specified output
+// file, from format flag or file extension.
+func outputFormat(path string) (string, error) {
+	if len(*format) > 0 {
+		switch *format {
+		case tmxFormat, tmjFormat:
+			return *format, nil
+		default:
+			return "", fmt.Errorf("invalid format: %s", *format)
+		}
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmx":
+		return tmxFormat, nil
+	case ".tmj", ".json":
+		return tmjFormat, nil
+	default:
+		return "", fmt.Errorf("unable to detect format for output file: %s", path)
+	}
+}
+
+// setEncoding sets specified data encoding and compression
+// for all tile layers from specified layers. Layers keep
+// their encoding if there is no encoding specified.
+// Compression is allowed only for base64 encoding.
+func setEncoding(layers []*layer, encoding, compression string) error {
+	switch encoding {
+	case "", "csv", "base64":
+	default:
+		return fmt.Errorf("invalid encoding: %s", encoding)
+	}
+	switch compression {
+	case "", "gzip", "zlib", "zstd":
+	default:
+		return fmt.Errorf("invalid compression: %s", compression)
+	}
+	for _, l := range layers {
+		if l.Type == tileLayer {
+			layerEncoding := encoding
+			if len(layerEncoding) < 1 {
+				layerEncoding = l.Encoding
+			}
+			if len(compression) > 0 && layerEncoding != "base64" {
+				return fmt.Errorf("layer: %s: compression requires base64 encoding",
+					l.Name)
+			}
+			l.Encoding = layerEncoding
+			l.Compression = compression
+		}
+		err := setEncoding(l.Layers, encoding, compression)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embed replaces all external tilesets of specified document
+// with tilesets read from tileset files.
+func embed(doc *document) error {
+	for i, ts := range doc.Tilesets {
+		if len(ts.Source) < 1 {
+			continue
+		}
+		data, err := os.ReadFile(ts.Source)
+		if err != nil {
+			return fmt.Errorf("unable to read tileset file: %v", err)
+		}
+		var embedded *tileset
+		if isXML(data) {
+			embedded, err = readTSX(data)
+		} else {
+			embedded, err = readTSJ(data)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read tileset: %s: %v", ts.Source, err)
+		}
+		embedded.paths(func(p *string) { *p = absPath(filepath.Dir(ts.Source), *p) })
+		embedded.FirstGID = ts.FirstGID
+		doc.Tilesets[i] = embedded
+	}
+	return nil
+}
+
+// externalize writes all tilesets embedded in specified
+// document to tileset files in specified directory and
+// replaces them with references to these files.
+func externalize(doc *document, dir, format string) error {
+	ext := ".tsx"
+	if format == tmjFormat {
+		ext = ".tsj"
+	}
+	written := make(map[string]bool)
+	for i, ts := range doc.Tilesets {
+		if len(ts.Source) > 0 {
+			continue
+		}
+		name := filepath.Base(ts.Name)
+		if len(ts.Name) < 1 || name == "." || name == ".." || name == string(filepath.Separator) {
+			name = fmt.Sprintf("tileset%d", i)
+		}
+		path := filepath.Join(dir, name+ext)
+		if written[path] {
+			return fmt.Errorf("tileset: %s: duplicate tileset file: %s", ts.Name, path)
+		}
+		ts.paths(func(p *string) { *p = relPath(dir, *p) })
+		var data []byte
+		var err error
+		if format == tmxFormat {
+			data, err = writeTSX(ts)
+		} else {
+			data, err = writeTSJ(ts)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to write tileset: %s: %v", ts.Name, err)
+		}
+		err = writeTileset(path, data)
+		if err != nil {
+			return fmt.Errorf("unable to write tileset file: %v", err)
+		}
+		written[path] = true
+		doc.Tilesets[i] = &tileset{FirstGID: ts.FirstGID, Source: path}
+	}
+	return nil
+}
+
+// writeTileset writes specified data to tileset file with
+// specified path. Existing file is overwritten only if force
+// flag is set.
+func writeTileset(path string, data []byte) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !*force {
+		flags |= os.O_EXCL
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("file already exists: %s, use -force to overwrite", path)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// isXML checks if specified data is an XML document.
+func isXML(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("<"))
+}
@@ -0,0 +1,108 @@
+/*
+ * main_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetEncoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		encoding    string
+		compression string
+		expected    string
+		err         bool
+	}{
+		{"csv", "base64", "csv", "", "csv", false},
+		{"base64 with compression", "csv", "base64", "zlib", "base64", false},
+		{"compression of base64 input", "base64", "", "zstd", "base64", false},
+		{"compression of csv input", "csv", "", "gzip", "", true},
+		{"csv with compression", "base64", "csv", "gzip", "", true},
+		{"invalid encoding", "csv", "xml", "", "", true},
+		{"invalid compression", "csv", "base64", "lzma", "", true},
+	}
+	for _, test := range tests {
+		l := &layer{Type: tileLayer, Name: "ground", Encoding: test.input}
+		group := &layer{Type: groupLayer, Name: "group", Layers: []*layer{l}}
+		err := setEncoding([]*layer{group}, test.encoding, test.compression)
+		if (err != nil) != test.err {
+			t.Errorf("%s: error: %v, expected error: %v", test.name, err, test.err)
+			continue
+		}
+		if test.err {
+			continue
+		}
+		if l.Encoding != test.expected || l.Compression != test.compression {
+			t.Errorf("%s: encoding: %s, compression: %s, expected: %s, %s",
+				test.name, l.Encoding, l.Compression, test.expected,
+				test.compression)
+		}
+	}
+}
+
+func TestExternalize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tiles.tsx")
+	err := os.WriteFile(path, []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newDoc := func() *document {
+		ts := &tileset{FirstGID: 1, Name: "tiles", TileWidth: 32,
+			TileHeight: 32}
+		return &document{Tilesets: []*tileset{ts}}
+	}
+	err = externalize(newDoc(), dir, tmxFormat)
+	if err == nil {
+		t.Fatal("no error for existing tileset file")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "old" {
+		t.Errorf("existing tileset file overwritten")
+	}
+	*force = true
+	defer func() { *force = false }()
+	doc := newDoc()
+	err = externalize(doc, dir, tmxFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Tilesets[0].Source != path {
+		t.Errorf("tileset source: %s, expected: %s", doc.Tilesets[0].Source, path)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) == "old" {
+		t.Errorf("existing tileset file not overwritten with force flag")
+	}
+}
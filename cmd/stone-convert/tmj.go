@@ -0,0 +1,742 @@
+/*
+ * tmj.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Regular expression for indented arrays of integers.
+var intArrayRegexp = regexp.MustCompile(`\[\s*-?\d+(\s*,\s*-?\d+)*\s*\]`)
+
+// Regular expression for whitespaces.
+var spaceRegexp = regexp.MustCompile(`\s+`)
+
+// Struct for TMJ map.
+type jsonMap struct {
+	Type            string         `json:"type"`
+	Version         any            `json:"version,omitempty"`
+	TiledVersion    string         `json:"tiledversion,omitempty"`
+	Class           string         `json:"class,omitempty"`
+	Orientation     string         `json:"orientation"`
+	RenderOrder     string         `json:"renderorder,omitempty"`
+	Width           int            `json:"width"`
+	Height          int            `json:"height"`
+	TileWidth       int            `json:"tilewidth"`
+	TileHeight      int            `json:"tileheight"`
+	Infinite        bool           `json:"infinite"`
+	BackgroundColor string         `json:"backgroundcolor,omitempty"`
+	NextLayerID     int            `json:"nextlayerid,omitempty"`
+	NextObjectID    int            `json:"nextobjectid,omitempty"`
+	Properties      []jsonProperty `json:"properties,omitempty"`
+	Tilesets        []jsonTileset  `json:"tilesets"`
+	Layers          []jsonLayer    `json:"layers"`
+}
+
+// Struct for TMJ property.
+type jsonProperty struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	PropertyType string `json:"propertytype,omitempty"`
+	Value        any    `json:"value"`
+}
+
+// Struct for TMJ tileset.
+type jsonTileset struct {
+	Type             string         `json:"type,omitempty"`
+	FirstGID         int            `json:"firstgid,omitempty"`
+	Source           string         `json:"source,omitempty"`
+	Name             string         `json:"name,omitempty"`
+	Class            string         `json:"class,omitempty"`
+	TileWidth        int            `json:"tilewidth,omitempty"`
+	TileHeight       int            `json:"tileheight,omitempty"`
+	Spacing          int            `json:"spacing,omitempty"`
+	Margin           int            `json:"margin,omitempty"`
+	TileCount        int            `json:"tilecount,omitempty"`
+	Columns          int            `json:"columns,omitempty"`
+	ObjectAlignment  string         `json:"objectalignment,omitempty"`
+	TileOffset       *jsonOffset    `json:"tileoffset,omitempty"`
+	Image            string         `json:"image,omitempty"`
+	ImageWidth       int            `json:"imagewidth,omitempty"`
+	ImageHeight      int            `json:"imageheight,omitempty"`
+	TransparentColor string         `json:"transparentcolor,omitempty"`
+	Properties       []jsonProperty `json:"properties,omitempty"`
+	Tiles            []jsonTile     `json:"tiles,omitempty"`
+	WangSets         []jsonWangSet  `json:"wangsets,omitempty"`
+}
+
+// Struct for TMJ tile offset.
+type jsonOffset struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Struct for TMJ tileset tile.
+type jsonTile struct {
+	ID          int            `json:"id"`
+	Type        string         `json:"type,omitempty"`
+	Class       string         `json:"class,omitempty"`
+	Probability float64        `json:"probability,omitempty"`
+	Properties  []jsonProperty `json:"properties,omitempty"`
+	Image       string         `json:"image,omitempty"`
+	ImageWidth  int            `json:"imagewidth,omitempty"`
+	ImageHeight int            `json:"imageheight,omitempty"`
+	ObjectGroup *jsonLayer     `json:"objectgroup,omitempty"`
+	Animation   []jsonFrame    `json:"animation,omitempty"`
+}
+
+// Struct for TMJ animation frame.
+type jsonFrame struct {
+	TileID   int `json:"tileid"`
+	Duration int `json:"duration"`
+}
+
+// Struct for TMJ Wang set.
+type jsonWangSet struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type,omitempty"`
+	Tile   int             `json:"tile"`
+	Colors []jsonWangColor `json:"colors,omitempty"`
+	Tiles  []jsonWangTile  `json:"wangtiles"`
+}
+
+// Struct for TMJ Wang color.
+type jsonWangColor struct {
+	Name        string  `json:"name"`
+	Color       string  `json:"color"`
+	Tile        int     `json:"tile"`
+	Probability float64 `json:"probability"`
+}
+
+// Struct for TMJ Wang tile.
+type jsonWangTile struct {
+	TileID int    `json:"tileid"`
+	WangID [8]int `json:"wangid"`
+}
+
+// Struct for TMJ layer of any type.
+type jsonLayer struct {
+	ID          int             `json:"id,omitempty"`
+	Name        string          `json:"name"`
+	Class       string          `json:"class,omitempty"`
+	Type        string          `json:"type"`
+	Opacity     *float64        `json:"opacity,omitempty"`
+	Visible     *bool           `json:"visible,omitempty"`
+	Locked      bool            `json:"locked,omitempty"`
+	X           int             `json:"x"`
+	Y           int             `json:"y"`
+	OffsetX     float64         `json:"offsetx,omitempty"`
+	OffsetY     float64         `json:"offsety,omitempty"`
+	ParallaxX   *float64        `json:"parallaxx,omitempty"`
+	ParallaxY   *float64        `json:"parallaxy,omitempty"`
+	TintColor   string          `json:"tintcolor,omitempty"`
+	Properties  []jsonProperty  `json:"properties,omitempty"`
+	Width       int             `json:"width,omitempty"`
+	Height      int             `json:"height,omitempty"`
+	Encoding    string          `json:"encoding,omitempty"`
+	Compression string          `json:"compression,omitempty"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	Chunks      json.RawMessage `json:"chunks,omitempty"`
+	Color       string          `json:"color,omitempty"`
+	DrawOrder   string          `json:"draworder,omitempty"`
+	Objects     *[]jsonObject   `json:"objects,omitempty"`
+	Image       string          `json:"image,omitempty"`
+	RepeatX     bool            `json:"repeatx,omitempty"`
+	RepeatY     bool            `json:"repeaty,omitempty"`
+	Layers      *[]jsonLayer    `json:"layers,omitempty"`
+}
+
+// Struct for TMJ object.
+type jsonObject struct {
+	ID         int            `json:"id,omitempty"`
+	Template   string         `json:"template,omitempty"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class,omitempty"`
+	GID        uint32         `json:"gid,omitempty"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	Rotation   float64        `json:"rotation"`
+	Visible    *bool          `json:"visible,omitempty"`
+	Ellipse    bool           `json:"ellipse,omitempty"`
+	Point      bool           `json:"point,omitempty"`
+	Polygon    []jsonPoint    `json:"polygon,omitempty"`
+	Polyline   []jsonPoint    `json:"polyline,omitempty"`
+	Text       *jsonText      `json:"text,omitempty"`
+	Properties []jsonProperty `json:"properties,omitempty"`
+}
+
+// Struct for TMJ polygon point.
+type jsonPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Struct for TMJ text object data.
+type jsonText struct {
+	Text       string `json:"text"`
+	FontFamily string `json:"fontfamily,omitempty"`
+	PixelSize  int    `json:"pixelsize,omitempty"`
+	Wrap       bool   `json:"wrap,omitempty"`
+	Color      string `json:"color,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Italic     bool   `json:"italic,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
+	Strikeout  bool   `json:"strikeout,omitempty"`
+	Kerning    *bool  `json:"kerning,omitempty"`
+	HAlign     string `json:"halign,omitempty"`
+	VAlign     string `json:"valign,omitempty"`
+}
+
+// readTMJ reads map document from specified TMJ data.
+func readTMJ(data []byte) (*document, error) {
+	jm := new(jsonMap)
+	err := json.Unmarshal(data, jm)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal TMJ: %v", err)
+	}
+	if jm.Infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+	d := &document{
+		TiledVersion:    jm.TiledVersion,
+		Class:           jm.Class,
+		Orientation:     jm.Orientation,
+		RenderOrder:     jm.RenderOrder,
+		Width:           jm.Width,
+		Height:          jm.Height,
+		TileWidth:       jm.TileWidth,
+		TileHeight:      jm.TileHeight,
+		BackgroundColor: jm.BackgroundColor,
+		NextLayerID:     jm.NextLayerID,
+		NextObjectID:    jm.NextObjectID,
+		Properties:      tmjProperties(jm.Properties),
+	}
+	if jm.Version != nil {
+		d.Version = fmt.Sprint(jm.Version)
+	}
+	for _, jts := range jm.Tilesets {
+		ts, err := tmjTileset(jts)
+		if err != nil {
+			return nil, fmt.Errorf("tileset: %s: %v", jts.Name, err)
+		}
+		d.Tilesets = append(d.Tilesets, ts)
+	}
+	for _, jl := range jm.Layers {
+		l, err := tmjLayer(jl)
+		if err != nil {
+			return nil, fmt.Errorf("layer: %s: %v", jl.Name, err)
+		}
+		d.Layers = append(d.Layers, l)
+	}
+	return d, nil
+}
+
+// readTSJ reads tileset from specified TSJ data.
+func readTSJ(data []byte) (*tileset, error) {
+	jts := jsonTileset{}
+	err := json.Unmarshal(data, &jts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal TSJ: %v", err)
+	}
+	return tmjTileset(jts)
+}
+
+// writeTMJ writes specified map document in TMJ format.
+func writeTMJ(d *document) ([]byte, error) {
+	jm := jsonMap{
+		Type:            "map",
+		TiledVersion:    d.TiledVersion,
+		Class:           d.Class,
+		Orientation:     d.Orientation,
+		RenderOrder:     d.RenderOrder,
+		Width:           d.Width,
+		Height:          d.Height,
+		TileWidth:       d.TileWidth,
+		TileHeight:      d.TileHeight,
+		BackgroundColor: d.BackgroundColor,
+		NextLayerID:     d.NextLayerID,
+		NextObjectID:    d.NextObjectID,
+		Tilesets:        make([]jsonTileset, 0),
+		Layers:          make([]jsonLayer, 0),
+	}
+	if len(d.Version) > 0 {
+		jm.Version = d.Version
+	}
+	var err error
+	jm.Properties, err = jsonProperties(d.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("map: %v", err)
+	}
+	for _, ts := range d.Tilesets {
+		jts, err := jsonTilesetData(ts)
+		if err != nil {
+			return nil, fmt.Errorf("tileset: %s: %v", ts.Name, err)
+		}
+		jm.Tilesets = append(jm.Tilesets, jts)
+	}
+	for _, l := range d.Layers {
+		jl, err := jsonLayerData(l)
+		if err != nil {
+			return nil, fmt.Errorf("layer: %s: %v", l.Name, err)
+		}
+		jm.Layers = append(jm.Layers, jl)
+	}
+	return marshalJSON(jm)
+}
+
+// writeTSJ writes specified tileset in TSJ format.
+func writeTSJ(ts *tileset) ([]byte, error) {
+	jts, err := jsonTilesetData(ts)
+	if err != nil {
+		return nil, err
+	}
+	jts.Type = "tileset"
+	jts.FirstGID = 0
+	return marshalJSON(jts)
+}
+
+// marshalJSON marshals specified value to indented JSON
+// document. Arrays of integers, like layer data, are kept
+// in single line, as in Tiled.
+func marshalJSON(v any) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	data = intArrayRegexp.ReplaceAllFunc(data, func(a []byte) []byte {
+		return spaceRegexp.ReplaceAll(a, nil)
+	})
+	return append(data, '\n'), nil
+}
+
+// tmjProperties converts specified TMJ properties.
+func tmjProperties(jps []jsonProperty) []property {
+	props := make([]property, 0, len(jps))
+	for _, jp := range jps {
+		p := property{Name: jp.Name, Type: jp.Type, PropertyType: jp.PropertyType}
+		if len(p.Type) < 1 {
+			p.Type = "string"
+		}
+		switch v := jp.Value.(type) {
+		case nil:
+		case string:
+			p.Value = v
+		case float64:
+			p.Value = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			p.Value = fmt.Sprint(v)
+		}
+		props = append(props, p)
+	}
+	return props
+}
+
+// jsonProperties converts specified properties to TMJ
+// properties.
+func jsonProperties(props []property) ([]jsonProperty, error) {
+	jps := make([]jsonProperty, 0, len(props))
+	for _, p := range props {
+		jp := jsonProperty{Name: p.Name, Type: p.Type, PropertyType: p.PropertyType,
+			Value: p.Value}
+		switch p.Type {
+		case "bool":
+			jp.Value = p.Value == "true"
+		case "int", "object":
+			if len(p.Value) < 1 {
+				jp.Value = 0
+				break
+			}
+			v, err := strconv.ParseInt(p.Value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("property: %s: invalid %s value: %s",
+					p.Name, p.Type, p.Value)
+			}
+			jp.Value = v
+		case "float":
+			if len(p.Value) < 1 {
+				jp.Value = 0
+				break
+			}
+			v, err := strconv.ParseFloat(p.Value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("property: %s: invalid %s value: %s",
+					p.Name, p.Type, p.Value)
+			}
+			jp.Value = v
+		case "class":
+			return nil, fmt.Errorf("property: %s: class properties are not supported",
+				p.Name)
+		}
+		jps = append(jps, jp)
+	}
+	return jps, nil
+}
+
+// tmjTileset converts specified TMJ tileset.
+func tmjTileset(jts jsonTileset) (*tileset, error) {
+	ts := &tileset{
+		FirstGID:        jts.FirstGID,
+		Source:          jts.Source,
+		Name:            jts.Name,
+		Class:           jts.Class,
+		TileWidth:       jts.TileWidth,
+		TileHeight:      jts.TileHeight,
+		Spacing:         jts.Spacing,
+		Margin:          jts.Margin,
+		TileCount:       jts.TileCount,
+		Columns:         jts.Columns,
+		ObjectAlignment: jts.ObjectAlignment,
+		Image:           tmjImage(jts.Image, jts.TransparentColor, jts.ImageWidth, jts.ImageHeight),
+		Properties:      tmjProperties(jts.Properties),
+	}
+	if jts.TileOffset != nil {
+		ts.TileOffset = &offset{jts.TileOffset.X, jts.TileOffset.Y}
+	}
+	for _, jt := range jts.Tiles {
+		t := &tile{ID: jt.ID, Type: jt.Type, Probability: jt.Probability,
+			Properties: tmjProperties(jt.Properties),
+			Image:      tmjImage(jt.Image, "", jt.ImageWidth, jt.ImageHeight)}
+		if len(t.Type) < 1 {
+			t.Type = jt.Class
+		}
+		if jt.ObjectGroup != nil {
+			og, err := tmjLayer(*jt.ObjectGroup)
+			if err != nil {
+				return nil, fmt.Errorf("tile: %d: %v", jt.ID, err)
+			}
+			t.ObjectGroup = og
+		}
+		for _, f := range jt.Animation {
+			t.Animation = append(t.Animation, frame{f.TileID, f.Duration})
+		}
+		ts.Tiles = append(ts.Tiles, t)
+	}
+	for _, jws := range jts.WangSets {
+		ws := &wangSet{Name: jws.Name, Type: jws.Type, Tile: jws.Tile}
+		for _, c := range jws.Colors {
+			ws.Colors = append(ws.Colors, wangColor{c.Name, c.Color, c.Tile,
+				c.Probability})
+		}
+		for _, t := range jws.Tiles {
+			ws.Tiles = append(ws.Tiles, wangTile{t.TileID, t.WangID})
+		}
+		ts.WangSets = append(ts.WangSets, ws)
+	}
+	return ts, nil
+}
+
+// jsonTilesetData converts specified tileset to TMJ tileset.
+func jsonTilesetData(ts *tileset) (jsonTileset, error) {
+	jts := jsonTileset{FirstGID: ts.FirstGID, Source: ts.Source}
+	if len(ts.Source) > 0 {
+		return jts, nil
+	}
+	jts.Name = ts.Name
+	jts.Class = ts.Class
+	jts.TileWidth = ts.TileWidth
+	jts.TileHeight = ts.TileHeight
+	jts.Spacing = ts.Spacing
+	jts.Margin = ts.Margin
+	jts.TileCount = ts.TileCount
+	jts.Columns = ts.Columns
+	jts.ObjectAlignment = ts.ObjectAlignment
+	if ts.TileOffset != nil {
+		jts.TileOffset = &jsonOffset{ts.TileOffset.X, ts.TileOffset.Y}
+	}
+	if ts.Image != nil {
+		jts.Image = ts.Image.Source
+		jts.TransparentColor = ts.Image.Trans
+		jts.ImageWidth = ts.Image.Width
+		jts.ImageHeight = ts.Image.Height
+	}
+	var err error
+	jts.Properties, err = jsonProperties(ts.Properties)
+	if err != nil {
+		return jts, err
+	}
+	for _, t := range ts.Tiles {
+		jt := jsonTile{ID: t.ID, Type: t.Type, Probability: t.Probability}
+		jt.Properties, err = jsonProperties(t.Properties)
+		if err != nil {
+			return jts, fmt.Errorf("tile: %d: %v", t.ID, err)
+		}
+		if t.Image != nil {
+			jt.Image = t.Image.Source
+			jt.ImageWidth = t.Image.Width
+			jt.ImageHeight = t.Image.Height
+		}
+		if t.ObjectGroup != nil {
+			og, err := jsonLayerData(t.ObjectGroup)
+			if err != nil {
+				return jts, fmt.Errorf("tile: %d: %v", t.ID, err)
+			}
+			jt.ObjectGroup = &og
+		}
+		for _, f := range t.Animation {
+			jt.Animation = append(jt.Animation, jsonFrame{f.TileID, f.Duration})
+		}
+		jts.Tiles = append(jts.Tiles, jt)
+	}
+	for _, ws := range ts.WangSets {
+		jws := jsonWangSet{Name: ws.Name, Type: ws.Type, Tile: ws.Tile,
+			Tiles: make([]jsonWangTile, 0, len(ws.Tiles))}
+		for _, c := range ws.Colors {
+			jws.Colors = append(jws.Colors, jsonWangColor{c.Name, c.Color, c.Tile,
+				c.Probability})
+		}
+		for _, t := range ws.Tiles {
+			jws.Tiles = append(jws.Tiles, jsonWangTile{t.TileID, t.WangID})
+		}
+		jts.WangSets = append(jts.WangSets, jws)
+	}
+	return jts, nil
+}
+
+// tmjImage creates image from specified TMJ image attributes,
+// returns nil if there is no image source.
+func tmjImage(source, trans string, width, height int) *image {
+	if len(source) < 1 {
+		return nil
+	}
+	return &image{source, trans, width, height}
+}
+
+// tmjLayer converts specified TMJ layer.
+func tmjLayer(jl jsonLayer) (*layer, error) {
+	l := newLayer(jl.Type)
+	l.ID = jl.ID
+	l.Name = jl.Name
+	l.Class = jl.Class
+	l.Locked = jl.Locked
+	l.OffsetX = jl.OffsetX
+	l.OffsetY = jl.OffsetY
+	l.TintColor = jl.TintColor
+	l.Properties = tmjProperties(jl.Properties)
+	if jl.Opacity != nil {
+		l.Opacity = *jl.Opacity
+	}
+	if jl.Visible != nil {
+		l.Visible = *jl.Visible
+	}
+	if jl.ParallaxX != nil {
+		l.ParallaxX = *jl.ParallaxX
+	}
+	if jl.ParallaxY != nil {
+		l.ParallaxY = *jl.ParallaxY
+	}
+	switch l.Type {
+	case tileLayer:
+		if len(jl.Chunks) > 0 {
+			return nil, fmt.Errorf("infinite maps are not supported")
+		}
+		l.Width = jl.Width
+		l.Height = jl.Height
+		l.Encoding = jl.Encoding
+		l.Compression = jl.Compression
+		if len(l.Encoding) < 1 {
+			l.Encoding = "csv"
+		}
+		if l.Encoding == "csv" {
+			err := json.Unmarshal(jl.Data, &l.Data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid data: %v", err)
+			}
+			break
+		}
+		var data string
+		err := json.Unmarshal(jl.Data, &data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %v", err)
+		}
+		l.Data, err = decodeData(data, l.Encoding, l.Compression)
+		if err != nil {
+			return nil, err
+		}
+	case objectGroup:
+		l.Color = jl.Color
+		l.DrawOrder = jl.DrawOrder
+		if jl.Objects == nil {
+			break
+		}
+		for _, jo := range *jl.Objects {
+			l.Objects = append(l.Objects, tmjObject(jo))
+		}
+	case imageLayer:
+		l.Image = tmjImage(jl.Image, "", 0, 0)
+		l.RepeatX = jl.RepeatX
+		l.RepeatY = jl.RepeatY
+	case groupLayer:
+		if jl.Layers == nil {
+			break
+		}
+		for _, jsl := range *jl.Layers {
+			sl, err := tmjLayer(jsl)
+			if err != nil {
+				return nil, fmt.Errorf("layer: %s: %v", jsl.Name, err)
+			}
+			l.Layers = append(l.Layers, sl)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported layer type: %s", l.Type)
+	}
+	return l, nil
+}
+
+// jsonLayerData converts specified layer to TMJ layer.
+func jsonLayerData(l *layer) (jsonLayer, error) {
+	jl := jsonLayer{ID: l.ID, Name: l.Name, Class: l.Class, Type: l.Type,
+		Opacity: &l.Opacity, Visible: &l.Visible, Locked: l.Locked,
+		OffsetX: l.OffsetX, OffsetY: l.OffsetY, TintColor: l.TintColor}
+	if l.ParallaxX != 1 {
+		jl.ParallaxX = &l.ParallaxX
+	}
+	if l.ParallaxY != 1 {
+		jl.ParallaxY = &l.ParallaxY
+	}
+	var err error
+	jl.Properties, err = jsonProperties(l.Properties)
+	if err != nil {
+		return jl, err
+	}
+	switch l.Type {
+	case tileLayer:
+		jl.Width = l.Width
+		jl.Height = l.Height
+		switch l.Encoding {
+		case "csv":
+			data := l.Data
+			if data == nil {
+				data = make([]uint32, 0)
+			}
+			jl.Data, err = json.Marshal(data)
+		case "base64":
+			jl.Encoding = l.Encoding
+			jl.Compression = l.Compression
+			var data string
+			data, err = encodeBase64(l.Data, l.Compression)
+			if err == nil {
+				jl.Data, err = json.Marshal(data)
+			}
+		default:
+			err = fmt.Errorf("unsupported encoding: %s", l.Encoding)
+		}
+		if err != nil {
+			return jl, err
+		}
+	case objectGroup:
+		jl.Color = l.Color
+		jl.DrawOrder = l.DrawOrder
+		objects := make([]jsonObject, 0, len(l.Objects))
+		for _, o := range l.Objects {
+			jo, err := jsonObjectData(o)
+			if err != nil {
+				return jl, fmt.Errorf("object: %d: %v", o.ID, err)
+			}
+			objects = append(objects, jo)
+		}
+		jl.Objects = &objects
+	case imageLayer:
+		if l.Image != nil {
+			jl.Image = l.Image.Source
+		}
+		jl.RepeatX = l.RepeatX
+		jl.RepeatY = l.RepeatY
+	case groupLayer:
+		layers := make([]jsonLayer, 0, len(l.Layers))
+		for _, sl := range l.Layers {
+			jsl, err := jsonLayerData(sl)
+			if err != nil {
+				return jl, fmt.Errorf("layer: %s: %v", sl.Name, err)
+			}
+			layers = append(layers, jsl)
+		}
+		jl.Layers = &layers
+	}
+	return jl, nil
+}
+
+// tmjObject converts specified TMJ object.
+func tmjObject(jo jsonObject) *object {
+	o := &object{ID: jo.ID, Name: jo.Name, Type: jo.Type, X: jo.X, Y: jo.Y,
+		Width: jo.Width, Height: jo.Height, Rotation: jo.Rotation, GID: jo.GID,
+		Visible: true, Template: jo.Template, Ellipse: jo.Ellipse, Point: jo.Point,
+		Properties: tmjProperties(jo.Properties)}
+	if len(o.Type) < 1 {
+		o.Type = jo.Class
+	}
+	if jo.Visible != nil {
+		o.Visible = *jo.Visible
+	}
+	if jo.Polygon != nil {
+		o.Polygon = make([]point, 0, len(jo.Polygon))
+		for _, p := range jo.Polygon {
+			o.Polygon = append(o.Polygon, point{p.X, p.Y})
+		}
+	}
+	if jo.Polyline != nil {
+		o.Polyline = make([]point, 0, len(jo.Polyline))
+		for _, p := range jo.Polyline {
+			o.Polyline = append(o.Polyline, point{p.X, p.Y})
+		}
+	}
+	if jt := jo.Text; jt != nil {
+		o.Text = &text{Text: jt.Text, FontFamily: jt.FontFamily,
+			PixelSize: jt.PixelSize, Wrap: jt.Wrap, Color: jt.Color, Bold: jt.Bold,
+			Italic: jt.Italic, Underline: jt.Underline, Strikeout: jt.Strikeout,
+			Kerning: jt.Kerning, HAlign: jt.HAlign, VAlign: jt.VAlign}
+	}
+	return o
+}
+
+// jsonObjectData converts specified object to TMJ object.
+func jsonObjectData(o *object) (jsonObject, error) {
+	jo := jsonObject{ID: o.ID, Template: o.Template, Name: o.Name, Type: o.Type,
+		GID: o.GID, X: o.X, Y: o.Y, Width: o.Width, Height: o.Height,
+		Rotation: o.Rotation, Visible: &o.Visible, Ellipse: o.Ellipse,
+		Point: o.Point}
+	var err error
+	jo.Properties, err = jsonProperties(o.Properties)
+	if err != nil {
+		return jo, err
+	}
+	for _, p := range o.Polygon {
+		jo.Polygon = append(jo.Polygon, jsonPoint{p.X, p.Y})
+	}
+	for _, p := range o.Polyline {
+		jo.Polyline = append(jo.Polyline, jsonPoint{p.X, p.Y})
+	}
+	if t := o.Text; t != nil {
+		jo.Text = &jsonText{Text: t.Text, FontFamily: t.FontFamily,
+			PixelSize: t.PixelSize, Wrap: t.Wrap, Color: t.Color, Bold: t.Bold,
+			Italic: t.Italic, Underline: t.Underline, Strikeout: t.Strikeout,
+			Kerning: t.Kerning, HAlign: t.HAlign, VAlign: t.VAlign}
+	}
+	return jo, nil
+}
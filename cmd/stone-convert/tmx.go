@@ -0,0 +1,856 @@
+/*
+ * tmx.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Struct for TMX map.
+type xmlMap struct {
+	XMLName         xml.Name       `xml:"map"`
+	Version         string         `xml:"version,attr,omitempty"`
+	TiledVersion    string         `xml:"tiledversion,attr,omitempty"`
+	Class           string         `xml:"class,attr,omitempty"`
+	Orientation     string         `xml:"orientation,attr"`
+	RenderOrder     string         `xml:"renderorder,attr,omitempty"`
+	Width           int            `xml:"width,attr"`
+	Height          int            `xml:"height,attr"`
+	TileWidth       int            `xml:"tilewidth,attr"`
+	TileHeight      int            `xml:"tileheight,attr"`
+	Infinite        int            `xml:"infinite,attr"`
+	BackgroundColor string         `xml:"backgroundcolor,attr,omitempty"`
+	NextLayerID     int            `xml:"nextlayerid,attr,omitempty"`
+	NextObjectID    int            `xml:"nextobjectid,attr,omitempty"`
+	Properties      *xmlProperties `xml:"properties"`
+	Tilesets        []xmlTileset   `xml:"tileset"`
+	Layers          []xmlLayer     `xml:",any"`
+}
+
+// Struct for TMX property.
+type xmlProperty struct {
+	Name         string         `xml:"name,attr"`
+	Type         string         `xml:"type,attr,omitempty"`
+	PropertyType string         `xml:"propertytype,attr,omitempty"`
+	Value        string         `xml:"value,attr,omitempty"`
+	Text         string         `xml:",chardata"`
+	Properties   *xmlProperties `xml:"properties"`
+}
+
+// Struct for TMX properties.
+type xmlProperties struct {
+	Properties []xmlProperty `xml:"property"`
+}
+
+// Struct for TMX tileset.
+type xmlTileset struct {
+	XMLName         xml.Name       `xml:"tileset"`
+	FirstGID        int            `xml:"firstgid,attr,omitempty"`
+	Source          string         `xml:"source,attr,omitempty"`
+	Name            string         `xml:"name,attr,omitempty"`
+	Class           string         `xml:"class,attr,omitempty"`
+	TileWidth       int            `xml:"tilewidth,attr,omitempty"`
+	TileHeight      int            `xml:"tileheight,attr,omitempty"`
+	Spacing         int            `xml:"spacing,attr,omitempty"`
+	Margin          int            `xml:"margin,attr,omitempty"`
+	TileCount       int            `xml:"tilecount,attr,omitempty"`
+	Columns         int            `xml:"columns,attr,omitempty"`
+	ObjectAlignment string         `xml:"objectalignment,attr,omitempty"`
+	TileOffset      *xmlOffset     `xml:"tileoffset"`
+	Properties      *xmlProperties `xml:"properties"`
+	Image           *xmlImage      `xml:"image"`
+	Tiles           []xmlTile      `xml:"tile"`
+	WangSets        *xmlWangSets   `xml:"wangsets"`
+}
+
+// Struct for TMX tile offset.
+type xmlOffset struct {
+	X int `xml:"x,attr"`
+	Y int `xml:"y,attr"`
+}
+
+// Struct for TMX image.
+type xmlImage struct {
+	Source string    `xml:"source,attr"`
+	Trans  string    `xml:"trans,attr,omitempty"`
+	Width  int       `xml:"width,attr,omitempty"`
+	Height int       `xml:"height,attr,omitempty"`
+	Data   *struct{} `xml:"data"`
+}
+
+// Struct for TMX tileset tile.
+type xmlTile struct {
+	ID          int            `xml:"id,attr"`
+	Type        string         `xml:"type,attr,omitempty"`
+	Class       string         `xml:"class,attr,omitempty"`
+	Probability float64        `xml:"probability,attr,omitempty"`
+	Properties  *xmlProperties `xml:"properties"`
+	Image       *xmlImage      `xml:"image"`
+	ObjectGroup *xmlLayer      `xml:"objectgroup"`
+	Animation   *xmlAnimation  `xml:"animation"`
+}
+
+// Struct for TMX tile animation.
+type xmlAnimation struct {
+	Frames []xmlFrame `xml:"frame"`
+}
+
+// Struct for TMX animation frame.
+type xmlFrame struct {
+	TileID   int `xml:"tileid,attr"`
+	Duration int `xml:"duration,attr"`
+}
+
+// Struct for TMX Wang sets.
+type xmlWangSets struct {
+	WangSets []xmlWangSet `xml:"wangset"`
+}
+
+// Struct for TMX Wang set.
+type xmlWangSet struct {
+	Name   string         `xml:"name,attr"`
+	Type   string         `xml:"type,attr,omitempty"`
+	Tile   int            `xml:"tile,attr"`
+	Colors []xmlWangColor `xml:"wangcolor"`
+	Tiles  []xmlWangTile  `xml:"wangtile"`
+}
+
+// Struct for TMX Wang color.
+type xmlWangColor struct {
+	Name        string  `xml:"name,attr"`
+	Color       string  `xml:"color,attr"`
+	Tile        int     `xml:"tile,attr"`
+	Probability float64 `xml:"probability,attr"`
+}
+
+// Struct for TMX Wang tile.
+type xmlWangTile struct {
+	TileID int    `xml:"tileid,attr"`
+	WangID string `xml:"wangid,attr"`
+}
+
+// Struct for TMX layer of any type.
+type xmlLayer struct {
+	XMLName    xml.Name
+	ID         int            `xml:"id,attr,omitempty"`
+	Name       string         `xml:"name,attr"`
+	Class      string         `xml:"class,attr,omitempty"`
+	Color      string         `xml:"color,attr,omitempty"`
+	Width      int            `xml:"width,attr,omitempty"`
+	Height     int            `xml:"height,attr,omitempty"`
+	Opacity    *float64       `xml:"opacity,attr,omitempty"`
+	Visible    *int           `xml:"visible,attr,omitempty"`
+	Locked     int            `xml:"locked,attr,omitempty"`
+	TintColor  string         `xml:"tintcolor,attr,omitempty"`
+	OffsetX    float64        `xml:"offsetx,attr,omitempty"`
+	OffsetY    float64        `xml:"offsety,attr,omitempty"`
+	ParallaxX  *float64       `xml:"parallaxx,attr,omitempty"`
+	ParallaxY  *float64       `xml:"parallaxy,attr,omitempty"`
+	RepeatX    int            `xml:"repeatx,attr,omitempty"`
+	RepeatY    int            `xml:"repeaty,attr,omitempty"`
+	DrawOrder  string         `xml:"draworder,attr,omitempty"`
+	Properties *xmlProperties `xml:"properties"`
+	Image      *xmlImage      `xml:"image"`
+	Data       *xmlData       `xml:"data"`
+	Objects    []xmlObject    `xml:"object"`
+	Layers     []xmlLayer     `xml:",any"`
+}
+
+// Struct for TMX tile layer data.
+type xmlData struct {
+	Encoding    string        `xml:"encoding,attr,omitempty"`
+	Compression string        `xml:"compression,attr,omitempty"`
+	Text        string        `xml:",innerxml"`
+	Tiles       []xmlDataTile `xml:"tile"`
+	Chunks      []struct{}    `xml:"chunk"`
+}
+
+// Struct for TMX tile in layer data with XML encoding.
+type xmlDataTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+// Struct for TMX object.
+type xmlObject struct {
+	ID         int            `xml:"id,attr,omitempty"`
+	Template   string         `xml:"template,attr,omitempty"`
+	Name       string         `xml:"name,attr,omitempty"`
+	Type       string         `xml:"type,attr,omitempty"`
+	Class      string         `xml:"class,attr,omitempty"`
+	GID        uint32         `xml:"gid,attr,omitempty"`
+	X          float64        `xml:"x,attr"`
+	Y          float64        `xml:"y,attr"`
+	Width      float64        `xml:"width,attr,omitempty"`
+	Height     float64        `xml:"height,attr,omitempty"`
+	Rotation   float64        `xml:"rotation,attr,omitempty"`
+	Visible    *int           `xml:"visible,attr,omitempty"`
+	Properties *xmlProperties `xml:"properties"`
+	Ellipse    *struct{}      `xml:"ellipse"`
+	Point      *struct{}      `xml:"point"`
+	Polygon    *xmlPoints     `xml:"polygon"`
+	Polyline   *xmlPoints     `xml:"polyline"`
+	Text       *xmlText       `xml:"text"`
+}
+
+// Struct for TMX polygon or polyline points.
+type xmlPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+// Struct for TMX text object data.
+type xmlText struct {
+	FontFamily string `xml:"fontfamily,attr,omitempty"`
+	PixelSize  int    `xml:"pixelsize,attr,omitempty"`
+	Wrap       int    `xml:"wrap,attr,omitempty"`
+	Color      string `xml:"color,attr,omitempty"`
+	Bold       int    `xml:"bold,attr,omitempty"`
+	Italic     int    `xml:"italic,attr,omitempty"`
+	Underline  int    `xml:"underline,attr,omitempty"`
+	Strikeout  int    `xml:"strikeout,attr,omitempty"`
+	Kerning    *int   `xml:"kerning,attr,omitempty"`
+	HAlign     string `xml:"halign,attr,omitempty"`
+	VAlign     string `xml:"valign,attr,omitempty"`
+	Text       string `xml:",chardata"`
+}
+
+// readTMX reads map document from specified TMX data.
+func readTMX(data []byte) (*document, error) {
+	xm := new(xmlMap)
+	err := xml.Unmarshal(data, xm)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal TMX: %v", err)
+	}
+	if xm.Infinite != 0 {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+	d := &document{
+		Version:         xm.Version,
+		TiledVersion:    xm.TiledVersion,
+		Class:           xm.Class,
+		Orientation:     xm.Orientation,
+		RenderOrder:     xm.RenderOrder,
+		Width:           xm.Width,
+		Height:          xm.Height,
+		TileWidth:       xm.TileWidth,
+		TileHeight:      xm.TileHeight,
+		BackgroundColor: xm.BackgroundColor,
+		NextLayerID:     xm.NextLayerID,
+		NextObjectID:    xm.NextObjectID,
+	}
+	d.Properties, err = tmxProperties(xm.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("map: %v", err)
+	}
+	for _, xts := range xm.Tilesets {
+		ts, err := tmxTileset(xts)
+		if err != nil {
+			return nil, fmt.Errorf("tileset: %s: %v", xts.Name, err)
+		}
+		d.Tilesets = append(d.Tilesets, ts)
+	}
+	d.Layers, err = tmxLayers(xm.Layers)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// readTSX reads tileset from specified TSX data.
+func readTSX(data []byte) (*tileset, error) {
+	xts := xmlTileset{}
+	err := xml.Unmarshal(data, &xts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal TSX: %v", err)
+	}
+	return tmxTileset(xts)
+}
+
+// writeTMX writes specified map document in TMX format.
+func writeTMX(d *document) ([]byte, error) {
+	xm := xmlMap{
+		Version:         d.Version,
+		TiledVersion:    d.TiledVersion,
+		Class:           d.Class,
+		Orientation:     d.Orientation,
+		RenderOrder:     d.RenderOrder,
+		Width:           d.Width,
+		Height:          d.Height,
+		TileWidth:       d.TileWidth,
+		TileHeight:      d.TileHeight,
+		BackgroundColor: d.BackgroundColor,
+		NextLayerID:     d.NextLayerID,
+		NextObjectID:    d.NextObjectID,
+		Properties:      xmlPropertiesData(d.Properties),
+	}
+	for _, ts := range d.Tilesets {
+		xm.Tilesets = append(xm.Tilesets, xmlTilesetData(ts))
+	}
+	for _, l := range d.Layers {
+		xl, err := xmlLayerData(l)
+		if err != nil {
+			return nil, fmt.Errorf("layer: %s: %v", l.Name, err)
+		}
+		xm.Layers = append(xm.Layers, xl)
+	}
+	return marshalXML(xm)
+}
+
+// writeTSX writes specified tileset in TSX format.
+func writeTSX(ts *tileset) ([]byte, error) {
+	xts := xmlTilesetData(ts)
+	xts.FirstGID = 0
+	return marshalXML(xts)
+}
+
+// marshalXML marshals specified value to indented XML
+// document.
+func marshalXML(v any) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	data = append([]byte(xml.Header), data...)
+	return append(data, '\n'), nil
+}
+
+// tmxProperties converts specified TMX properties.
+func tmxProperties(xps *xmlProperties) ([]property, error) {
+	if xps == nil {
+		return nil, nil
+	}
+	props := make([]property, 0, len(xps.Properties))
+	for _, xp := range xps.Properties {
+		if xp.Type == "class" || xp.Properties != nil {
+			return nil, fmt.Errorf("property: %s: class properties are not supported",
+				xp.Name)
+		}
+		p := property{Name: xp.Name, Type: xp.Type, PropertyType: xp.PropertyType,
+			Value: xp.Value}
+		if len(p.Type) < 1 {
+			p.Type = "string"
+		}
+		if len(p.Value) < 1 {
+			p.Value = xp.Text
+		}
+		props = append(props, p)
+	}
+	return props, nil
+}
+
+// xmlProperties converts specified properties to TMX
+// properties.
+func xmlPropertiesData(props []property) *xmlProperties {
+	if len(props) < 1 {
+		return nil
+	}
+	xps := new(xmlProperties)
+	for _, p := range props {
+		xp := xmlProperty{Name: p.Name, Type: p.Type, PropertyType: p.PropertyType}
+		if p.Type == "string" && strings.Contains(p.Value, "\n") {
+			xp.Text = p.Value
+		} else {
+			xp.Value = p.Value
+		}
+		if xp.Type == "string" {
+			xp.Type = ""
+		}
+		xps.Properties = append(xps.Properties, xp)
+	}
+	return xps
+}
+
+// tmxTileset converts specified TMX tileset.
+func tmxTileset(xts xmlTileset) (*tileset, error) {
+	ts := &tileset{
+		FirstGID:        xts.FirstGID,
+		Source:          xts.Source,
+		Name:            xts.Name,
+		Class:           xts.Class,
+		TileWidth:       xts.TileWidth,
+		TileHeight:      xts.TileHeight,
+		Spacing:         xts.Spacing,
+		Margin:          xts.Margin,
+		TileCount:       xts.TileCount,
+		Columns:         xts.Columns,
+		ObjectAlignment: xts.ObjectAlignment,
+	}
+	if xts.TileOffset != nil {
+		ts.TileOffset = &offset{xts.TileOffset.X, xts.TileOffset.Y}
+	}
+	var err error
+	ts.Image, err = tmxImage(xts.Image)
+	if err != nil {
+		return nil, err
+	}
+	ts.Properties, err = tmxProperties(xts.Properties)
+	if err != nil {
+		return nil, err
+	}
+	for _, xt := range xts.Tiles {
+		t := &tile{ID: xt.ID, Type: xt.Type, Probability: xt.Probability}
+		if len(t.Type) < 1 {
+			t.Type = xt.Class
+		}
+		t.Properties, err = tmxProperties(xt.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("tile: %d: %v", xt.ID, err)
+		}
+		t.Image, err = tmxImage(xt.Image)
+		if err != nil {
+			return nil, fmt.Errorf("tile: %d: %v", xt.ID, err)
+		}
+		if xt.ObjectGroup != nil {
+			t.ObjectGroup, err = tmxLayer(*xt.ObjectGroup)
+			if err != nil {
+				return nil, fmt.Errorf("tile: %d: %v", xt.ID, err)
+			}
+		}
+		for _, f := range xt.Animation.frames() {
+			t.Animation = append(t.Animation, frame{f.TileID, f.Duration})
+		}
+		ts.Tiles = append(ts.Tiles, t)
+	}
+	for _, xws := range xts.WangSets.wangSets() {
+		ws := &wangSet{Name: xws.Name, Type: xws.Type, Tile: xws.Tile}
+		for _, c := range xws.Colors {
+			ws.Colors = append(ws.Colors, wangColor{c.Name, c.Color, c.Tile,
+				c.Probability})
+		}
+		for _, t := range xws.Tiles {
+			wangID, err := parseWangID(t.WangID)
+			if err != nil {
+				return nil, fmt.Errorf("Wang set: %s: invalid Wang ID: %s: %v",
+					xws.Name, t.WangID, err)
+			}
+			ws.Tiles = append(ws.Tiles, wangTile{t.TileID, wangID})
+		}
+		ts.WangSets = append(ts.WangSets, ws)
+	}
+	return ts, nil
+}
+
+// xmlTilesetData converts specified tileset to TMX tileset.
+func xmlTilesetData(ts *tileset) xmlTileset {
+	xts := xmlTileset{FirstGID: ts.FirstGID, Source: ts.Source}
+	if len(ts.Source) > 0 {
+		return xts
+	}
+	xts.Name = ts.Name
+	xts.Class = ts.Class
+	xts.TileWidth = ts.TileWidth
+	xts.TileHeight = ts.TileHeight
+	xts.Spacing = ts.Spacing
+	xts.Margin = ts.Margin
+	xts.TileCount = ts.TileCount
+	xts.Columns = ts.Columns
+	xts.ObjectAlignment = ts.ObjectAlignment
+	if ts.TileOffset != nil {
+		xts.TileOffset = &xmlOffset{ts.TileOffset.X, ts.TileOffset.Y}
+	}
+	xts.Properties = xmlPropertiesData(ts.Properties)
+	xts.Image = xmlImageData(ts.Image)
+	for _, t := range ts.Tiles {
+		xt := xmlTile{ID: t.ID, Type: t.Type, Probability: t.Probability,
+			Properties: xmlPropertiesData(t.Properties), Image: xmlImageData(t.Image)}
+		if t.ObjectGroup != nil {
+			og, _ := xmlLayerData(t.ObjectGroup)
+			xt.ObjectGroup = &og
+		}
+		if len(t.Animation) > 0 {
+			xt.Animation = new(xmlAnimation)
+		}
+		for _, f := range t.Animation {
+			xt.Animation.Frames = append(xt.Animation.Frames, xmlFrame{f.TileID, f.Duration})
+		}
+		xts.Tiles = append(xts.Tiles, xt)
+	}
+	if len(ts.WangSets) > 0 {
+		xts.WangSets = new(xmlWangSets)
+	}
+	for _, ws := range ts.WangSets {
+		xws := xmlWangSet{Name: ws.Name, Type: ws.Type, Tile: ws.Tile}
+		for _, c := range ws.Colors {
+			xws.Colors = append(xws.Colors, xmlWangColor{c.Name, c.Color, c.Tile,
+				c.Probability})
+		}
+		for _, t := range ws.Tiles {
+			values := make([]string, len(t.WangID))
+			for i, v := range t.WangID {
+				values[i] = strconv.Itoa(v)
+			}
+			xws.Tiles = append(xws.Tiles, xmlWangTile{t.TileID,
+				strings.Join(values, ",")})
+		}
+		xts.WangSets.WangSets = append(xts.WangSets.WangSets, xws)
+	}
+	return xts
+}
+
+// frames returns animation frames, or nil if animation
+// is nil.
+func (xa *xmlAnimation) frames() []xmlFrame {
+	if xa == nil {
+		return nil
+	}
+	return xa.Frames
+}
+
+// wangSets returns Wang sets, or nil if Wang sets
+// are nil.
+func (xws *xmlWangSets) wangSets() []xmlWangSet {
+	if xws == nil {
+		return nil
+	}
+	return xws.WangSets
+}
+
+// tmxImage converts specified TMX image.
+func tmxImage(xi *xmlImage) (*image, error) {
+	if xi == nil {
+		return nil, nil
+	}
+	if xi.Data != nil {
+		return nil, fmt.Errorf("embedded images are not supported")
+	}
+	return &image{xi.Source, xi.Trans, xi.Width, xi.Height}, nil
+}
+
+// xmlImageData converts specified image to TMX image.
+func xmlImageData(i *image) *xmlImage {
+	if i == nil {
+		return nil
+	}
+	return &xmlImage{Source: i.Source, Trans: i.Trans, Width: i.Width,
+		Height: i.Height}
+}
+
+// tmxLayers converts specified TMX layers, elements other
+// than layers are skipped.
+func tmxLayers(xls []xmlLayer) ([]*layer, error) {
+	layers := make([]*layer, 0)
+	for _, xl := range xls {
+		switch xl.XMLName.Local {
+		case "layer", objectGroup, imageLayer, groupLayer:
+		default:
+			continue
+		}
+		l, err := tmxLayer(xl)
+		if err != nil {
+			return nil, fmt.Errorf("layer: %s: %v", xl.Name, err)
+		}
+		layers = append(layers, l)
+	}
+	return layers, nil
+}
+
+// tmxLayer converts specified TMX layer.
+func tmxLayer(xl xmlLayer) (*layer, error) {
+	layerType := xl.XMLName.Local
+	if layerType == "layer" {
+		layerType = tileLayer
+	}
+	l := newLayer(layerType)
+	l.ID = xl.ID
+	l.Name = xl.Name
+	l.Class = xl.Class
+	l.Locked = xl.Locked != 0
+	l.TintColor = xl.TintColor
+	l.OffsetX = xl.OffsetX
+	l.OffsetY = xl.OffsetY
+	if xl.Opacity != nil {
+		l.Opacity = *xl.Opacity
+	}
+	if xl.Visible != nil {
+		l.Visible = *xl.Visible != 0
+	}
+	if xl.ParallaxX != nil {
+		l.ParallaxX = *xl.ParallaxX
+	}
+	if xl.ParallaxY != nil {
+		l.ParallaxY = *xl.ParallaxY
+	}
+	var err error
+	l.Properties, err = tmxProperties(xl.Properties)
+	if err != nil {
+		return nil, err
+	}
+	switch l.Type {
+	case tileLayer:
+		l.Width = xl.Width
+		l.Height = xl.Height
+		if xl.Data == nil {
+			return l, nil
+		}
+		if len(xl.Data.Chunks) > 0 {
+			return nil, fmt.Errorf("infinite maps are not supported")
+		}
+		l.Encoding = xl.Data.Encoding
+		l.Compression = xl.Data.Compression
+		if len(l.Encoding) < 1 {
+			// Deprecated XML encoding.
+			l.Encoding = "csv"
+			for _, t := range xl.Data.Tiles {
+				l.Data = append(l.Data, t.GID)
+			}
+			return l, nil
+		}
+		l.Data, err = decodeData(xl.Data.Text, l.Encoding, l.Compression)
+		if err != nil {
+			return nil, err
+		}
+	case objectGroup:
+		l.Color = xl.Color
+		l.DrawOrder = xl.DrawOrder
+		for _, xo := range xl.Objects {
+			o, err := tmxObject(xo)
+			if err != nil {
+				return nil, fmt.Errorf("object: %d: %v", xo.ID, err)
+			}
+			l.Objects = append(l.Objects, o)
+		}
+	case imageLayer:
+		l.Image, err = tmxImage(xl.Image)
+		if err != nil {
+			return nil, err
+		}
+		l.RepeatX = xl.RepeatX != 0
+		l.RepeatY = xl.RepeatY != 0
+	case groupLayer:
+		l.Layers, err = tmxLayers(xl.Layers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// xmlLayerData converts specified layer to TMX layer.
+func xmlLayerData(l *layer) (xmlLayer, error) {
+	xl := xmlLayer{ID: l.ID, Name: l.Name, Class: l.Class, TintColor: l.TintColor,
+		OffsetX: l.OffsetX, OffsetY: l.OffsetY, Properties: xmlPropertiesData(l.Properties)}
+	xl.XMLName.Local = l.Type
+	if l.Locked {
+		xl.Locked = 1
+	}
+	if l.Opacity != 1 {
+		xl.Opacity = &l.Opacity
+	}
+	if !l.Visible {
+		xl.Visible = new(int)
+	}
+	if l.ParallaxX != 1 {
+		xl.ParallaxX = &l.ParallaxX
+	}
+	if l.ParallaxY != 1 {
+		xl.ParallaxY = &l.ParallaxY
+	}
+	switch l.Type {
+	case tileLayer:
+		xl.XMLName.Local = "layer"
+		xl.Width = l.Width
+		xl.Height = l.Height
+		xl.Data = &xmlData{Encoding: l.Encoding, Compression: l.Compression}
+		switch l.Encoding {
+		case "csv":
+			xl.Data.Text = encodeCSV(l.Data, l.Width)
+		case "base64":
+			data, err := encodeBase64(l.Data, l.Compression)
+			if err != nil {
+				return xl, err
+			}
+			xl.Data.Text = data
+		default:
+			return xl, fmt.Errorf("unsupported encoding: %s", l.Encoding)
+		}
+	case objectGroup:
+		xl.Color = l.Color
+		xl.DrawOrder = l.DrawOrder
+		for _, o := range l.Objects {
+			xl.Objects = append(xl.Objects, xmlObjectData(o))
+		}
+	case imageLayer:
+		xl.Image = xmlImageData(l.Image)
+		if l.RepeatX {
+			xl.RepeatX = 1
+		}
+		if l.RepeatY {
+			xl.RepeatY = 1
+		}
+	case groupLayer:
+		for _, sl := range l.Layers {
+			xsl, err := xmlLayerData(sl)
+			if err != nil {
+				return xl, fmt.Errorf("layer: %s: %v", sl.Name, err)
+			}
+			xl.Layers = append(xl.Layers, xsl)
+		}
+	}
+	return xl, nil
+}
+
+// tmxObject converts specified TMX object.
+func tmxObject(xo xmlObject) (*object, error) {
+	o := &object{ID: xo.ID, Name: xo.Name, Type: xo.Type, X: xo.X, Y: xo.Y,
+		Width: xo.Width, Height: xo.Height, Rotation: xo.Rotation, GID: xo.GID,
+		Visible: true, Template: xo.Template, Ellipse: xo.Ellipse != nil,
+		Point: xo.Point != nil}
+	if len(o.Type) < 1 {
+		o.Type = xo.Class
+	}
+	if xo.Visible != nil {
+		o.Visible = *xo.Visible != 0
+	}
+	var err error
+	o.Properties, err = tmxProperties(xo.Properties)
+	if err != nil {
+		return nil, err
+	}
+	if xo.Polygon != nil {
+		o.Polygon, err = parsePoints(xo.Polygon.Points)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polygon: %v", err)
+		}
+	}
+	if xo.Polyline != nil {
+		o.Polyline, err = parsePoints(xo.Polyline.Points)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polyline: %v", err)
+		}
+	}
+	if xt := xo.Text; xt != nil {
+		o.Text = &text{Text: xt.Text, FontFamily: xt.FontFamily,
+			PixelSize: xt.PixelSize, Wrap: xt.Wrap != 0, Color: xt.Color,
+			Bold: xt.Bold != 0, Italic: xt.Italic != 0, Underline: xt.Underline != 0,
+			Strikeout: xt.Strikeout != 0, HAlign: xt.HAlign, VAlign: xt.VAlign}
+		if xt.Kerning != nil {
+			kerning := *xt.Kerning != 0
+			o.Text.Kerning = &kerning
+		}
+	}
+	return o, nil
+}
+
+// xmlObjectData converts specified object to TMX object.
+func xmlObjectData(o *object) xmlObject {
+	xo := xmlObject{ID: o.ID, Template: o.Template, Name: o.Name, Type: o.Type,
+		GID: o.GID, X: o.X, Y: o.Y, Width: o.Width, Height: o.Height,
+		Rotation: o.Rotation, Properties: xmlPropertiesData(o.Properties)}
+	if !o.Visible {
+		xo.Visible = new(int)
+	}
+	if o.Ellipse {
+		xo.Ellipse = &struct{}{}
+	}
+	if o.Point {
+		xo.Point = &struct{}{}
+	}
+	if o.Polygon != nil {
+		xo.Polygon = &xmlPoints{formatPoints(o.Polygon)}
+	}
+	if o.Polyline != nil {
+		xo.Polyline = &xmlPoints{formatPoints(o.Polyline)}
+	}
+	if t := o.Text; t != nil {
+		xo.Text = &xmlText{Text: t.Text, FontFamily: t.FontFamily,
+			PixelSize: t.PixelSize, Wrap: boolInt(t.Wrap), Color: t.Color,
+			Bold: boolInt(t.Bold), Italic: boolInt(t.Italic),
+			Underline: boolInt(t.Underline), Strikeout: boolInt(t.Strikeout),
+			HAlign: t.HAlign, VAlign: t.VAlign}
+		if t.Kerning != nil {
+			kerning := boolInt(*t.Kerning)
+			xo.Text.Kerning = &kerning
+		}
+	}
+	return xo
+}
+
+// parsePoints parses points from specified TMX points
+// attribute.
+func parsePoints(s string) ([]point, error) {
+	points := make([]point, 0)
+	for _, p := range strings.Fields(s) {
+		xy := strings.Split(p, ",")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("invalid point: %s", p)
+		}
+		x, err := strconv.ParseFloat(xy[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point: %s", p)
+		}
+		y, err := strconv.ParseFloat(xy[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point: %s", p)
+		}
+		points = append(points, point{x, y})
+	}
+	return points, nil
+}
+
+// formatPoints formats specified points to TMX points
+// attribute.
+func formatPoints(points []point) string {
+	values := make([]string, len(points))
+	for i, p := range points {
+		values[i] = strconv.FormatFloat(p.X, 'f', -1, 64) + "," +
+			strconv.FormatFloat(p.Y, 'f', -1, 64)
+	}
+	return strings.Join(values, " ")
+}
+
+// parseWangID parses Wang ID from specified TMX attribute,
+// in current or legacy hex format.
+func parseWangID(s string) (wangID [8]int, err error) {
+	if strings.HasPrefix(s, "0x") {
+		val, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil {
+			return wangID, err
+		}
+		for i := range wangID {
+			wangID[i] = int(val >> (4 * i) & 0xF)
+		}
+		return wangID, nil
+	}
+	values := strings.Split(s, ",")
+	if len(values) != len(wangID) {
+		return wangID, fmt.Errorf("invalid number of values: %d", len(values))
+	}
+	for i, v := range values {
+		wangID[i], err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return wangID, err
+		}
+	}
+	return wangID, nil
+}
+
+// boolInt returns 1 for true and 0 for false.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+/*
+ * main.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Stone-info is command-line tool for printing information
+// about TMX map, as loaded by stone.
+//
+// Usage:
+//
+//	stone-info [flags] path/to/map.tmx
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/isangeles/stone"
+)
+
+var jsonOut = flag.Bool("json", false, "print information in JSON format")
+
+// Struct for map information.
+type mapInfo struct {
+	Path         string            `json:"path"`
+	Orientation  string            `json:"orientation"`
+	Width        int               `json:"width"`
+	Height       int               `json:"height"`
+	TileWidth    int               `json:"tile-width"`
+	TileHeight   int               `json:"tile-height"`
+	Tilesets     []tilesetInfo     `json:"tilesets"`
+	Layers       []layerInfo       `json:"layers"`
+	ObjectLayers []objectLayerInfo `json:"object-layers"`
+}
+
+// Struct for tileset information.
+type tilesetInfo struct {
+	Name       string `json:"name"`
+	FirstGID   int    `json:"first-gid"`
+	TileWidth  int    `json:"tile-width"`
+	TileHeight int    `json:"tile-height"`
+	Tiles      int    `json:"tiles"`
+}
+
+// Struct for tile layer information.
+type layerInfo struct {
+	Name       string            `json:"name"`
+	Tiles      int               `json:"tiles"`
+	Properties map[string]string `json:"properties"`
+}
+
+// Struct for object layer information.
+type objectLayerInfo struct {
+	Name       string            `json:"name"`
+	Objects    int               `json:"objects"`
+	Properties map[string]string `json:"properties"`
+}
+
+// Main function.
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] map.tmx\n",
+			filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	m, err := stone.NewMap(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stone-info: unable to create map: %v\n", err)
+		os.Exit(1)
+	}
+	info := newMapInfo(flag.Arg(0), m)
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(info)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "stone-info: unable to encode info: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printInfo(os.Stdout, info)
+}
+
+// newMapInfo creates information about specified map.
+func newMapInfo(path string, m *stone.Map) mapInfo {
+	info := mapInfo{
+		Path:        path,
+		Orientation: m.Orientation(),
+		Width:       int(m.TilesCount().X),
+		Height:      int(m.TilesCount().Y),
+		TileWidth:   int(m.TileSize().X),
+		TileHeight:  int(m.TileSize().Y),
+	}
+	for _, ts := range m.Tilesets() {
+		info.Tilesets = append(info.Tilesets, tilesetInfo{
+			Name:       ts.Name(),
+			FirstGID:   ts.FirstGID(),
+			TileWidth:  int(ts.TileSize().X),
+			TileHeight: int(ts.TileSize().Y),
+			Tiles:      ts.TileCount(),
+		})
+	}
+	for _, l := range m.Layers() {
+		info.Layers = append(info.Layers, layerInfo{
+			Name:       l.Name(),
+			Tiles:      len(l.Tiles()),
+			Properties: l.Properties(),
+		})
+	}
+	for _, ol := range m.ObjectLayers() {
+		info.ObjectLayers = append(info.ObjectLayers, objectLayerInfo{
+			Name:       ol.Name(),
+			Objects:    len(ol.Objects()),
+			Properties: ol.Properties(),
+		})
+	}
+	return info
+}
+
+// printInfo prints specified map information in
+// human-readable format.
+func printInfo(w io.Writer, info mapInfo) {
+	fmt.Fprintf(w, "Map: %s\n", info.Path)
+	fmt.Fprintf(w, "Orientation: %s\n", info.Orientation)
+	fmt.Fprintf(w, "Size: %dx%d tiles\n", info.Width, info.Height)
+	fmt.Fprintf(w, "Tile size: %dx%d\n", info.TileWidth, info.TileHeight)
+	fmt.Fprintf(w, "Tilesets: %d\n", len(info.Tilesets))
+	for _, ts := range info.Tilesets {
+		fmt.Fprintf(w, "\t%s: first GID: %d, tile size: %dx%d, tiles: %d\n",
+			ts.Name, ts.FirstGID, ts.TileWidth, ts.TileHeight, ts.Tiles)
+	}
+	fmt.Fprintf(w, "Layers: %d\n", len(info.Layers))
+	for _, l := range info.Layers {
+		fmt.Fprintf(w, "\t%s: tiles: %d%s\n", l.Name, l.Tiles,
+			propertiesSummary(l.Properties))
+	}
+	fmt.Fprintf(w, "Object layers: %d\n", len(info.ObjectLayers))
+	for _, ol := range info.ObjectLayers {
+		fmt.Fprintf(w, "\t%s: objects: %d%s\n", ol.Name, ol.Objects,
+			propertiesSummary(ol.Properties))
+	}
+}
+
+// propertiesSummary returns summary of specified properties,
+// sorted by name, or empty string if there is no properties.
+func propertiesSummary(props map[string]string) string {
+	if len(props) < 1 {
+		return ""
+	}
+	pairs := make([]string, 0, len(props))
+	for k, v := range props {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return ", properties: " + strings.Join(pairs, " ")
+}
@@ -0,0 +1,95 @@
+/*
+ * main.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Stone-lint is command-line tool for validating TMX maps.
+// Exits with status 1 if any issue was found.
+//
+// Usage:
+//
+//	stone-lint [flags] path/to/map.tmx...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/isangeles/stone"
+)
+
+var schemaPath = flag.String("schema", "", "JSON file with map schema")
+
+// Main function.
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] map.tmx...\n",
+			filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var schema *stone.Schema
+	if len(*schemaPath) > 0 {
+		s, err := readSchema(*schemaPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "stone-lint: unable to read schema: %v\n", err)
+			os.Exit(2)
+		}
+		schema = s
+	}
+	failed := false
+	for _, path := range flag.Args() {
+		issues, err := stone.Validate(path, schema)
+		if err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		for _, i := range issues {
+			fmt.Printf("%s: %s\n", path, i)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// readSchema reads map schema from JSON file with specified path.
+func readSchema(path string) (*stone.Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema := new(stone.Schema)
+	err = json.Unmarshal(data, schema)
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
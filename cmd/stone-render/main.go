@@ -0,0 +1,154 @@
+/*
+ * main.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Stone-render is command-line tool for rendering TMX map to PNG image.
+//
+// Usage:
+//
+//	stone-render [flags] path/to/map.tmx
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gopxl/pixel"
+
+	"github.com/isangeles/stone"
+)
+
+var (
+	out        = flag.String("o", "", "output PNG file, map file name with .png extension by default")
+	layers     = flag.String("layers", "", "comma-separated names of layers to render, all layers by default")
+	rect       = flag.String("rect", "", "map area to render in pixels, as in Tiled: x,y,width,height")
+	scale      = flag.Float64("scale", 1.0, "image scale")
+	background = flag.Bool("background", false, "fill rendered area with map background color")
+)
+
+// Main function.
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] map.tmx\n",
+			filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	err := render(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stone-render: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// render renders map from specified TMX file to PNG file.
+func render(path string) error {
+	if *scale <= 0 {
+		return fmt.Errorf("invalid scale: %f", *scale)
+	}
+	tmxMap, err := stone.NewMap(path, stone.WithBackground(*background))
+	if err != nil {
+		return fmt.Errorf("unable to create map: %v", err)
+	}
+	area := tmxMap.Bounds().Union(tmxMap.DrawBounds())
+	if len(*rect) > 0 {
+		area, err = parseRect(tmxMap, *rect)
+		if err != nil {
+			return fmt.Errorf("invalid rect: %v", err)
+		}
+	}
+	mapLayers, err := selectLayers(tmxMap, *layers)
+	if err != nil {
+		return err
+	}
+	img := tmxMap.DrawImage(area, *scale, mapLayers...)
+	outPath := *out
+	if len(outPath) < 1 {
+		outPath = strings.TrimSuffix(path, filepath.Ext(path)) + ".png"
+	}
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("unable to create output file: %v", err)
+	}
+	err = png.Encode(file, img)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("unable to encode image: %v", err)
+	}
+	return file.Close()
+}
+
+// selectLayers returns map layers with names from specified
+// comma-separated list.
+func selectLayers(tmxMap *stone.Map, names string) ([]*stone.Layer, error) {
+	if len(names) < 1 {
+		return nil, nil
+	}
+	selected := make([]*stone.Layer, 0)
+	for _, name := range strings.Split(names, ",") {
+		var layer *stone.Layer
+		for _, l := range tmxMap.Layers() {
+			if l.Name() == strings.TrimSpace(name) {
+				layer = l
+				break
+			}
+		}
+		if layer == nil {
+			return nil, fmt.Errorf("layer not found: %s", name)
+		}
+		selected = append(selected, layer)
+	}
+	return selected, nil
+}
+
+// parseRect parses specified rectangle in map pixels, with
+// origin in the top-left corner of the map, and translates
+// it to map area.
+func parseRect(tmxMap *stone.Map, s string) (pixel.Rect, error) {
+	values := strings.Split(s, ",")
+	if len(values) != 4 {
+		return pixel.ZR, fmt.Errorf("expected 4 values: %s", s)
+	}
+	var v [4]float64
+	for i := range values {
+		val, err := strconv.ParseFloat(strings.TrimSpace(values[i]), 64)
+		if err != nil {
+			return pixel.ZR, err
+		}
+		v[i] = val
+	}
+	if v[2] <= 0 || v[3] <= 0 {
+		return pixel.ZR, fmt.Errorf("invalid size: %fx%f", v[2], v[3])
+	}
+	bounds := tmxMap.Bounds()
+	min := pixel.V(bounds.Min.X+v[0], bounds.Max.Y-v[1]-v[3])
+	return pixel.R(min.X, min.Y, min.X+v[2], min.Y+v[3]), nil
+}
@@ -0,0 +1,170 @@
+/*
+ * collision.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"math"
+	"strconv"
+	"sync"
+)
+
+const (
+	// Name of layer or tile property that marks tiles
+	// as blocking.
+	CollisionProperty = "collision"
+	// Name of layer or tile property with cost of
+	// moving through tiles.
+	CostProperty = "cost"
+)
+
+// Struct for cache of the lowest move cost on the map.
+type moveCostCache struct {
+	mutex sync.Mutex
+	cost  float64
+	valid bool
+}
+
+// Blocked checks if map cell with specified grid coordinates
+// is blocked. Cell is blocked if it contains tile with the
+// collision property set to true, or tile from layer with
+// this property set to true. Cells with invalid move cost,
+// i.e. negative, infinite or not a number, are blocked too. Cells outside the map
+// are always blocked.
+func (m *Map) Blocked(x, y int) bool {
+	if !m.cellOnMap(x, y) {
+		return true
+	}
+	if _, ok := m.cellCost(x, y); !ok {
+		return true
+	}
+	return m.blockingTile(x, y) != nil
+}
+
+// MoveCost returns cost of moving through map cell with
+// specified grid coordinates. Cost is taken from the cost
+// property of the top tile in this cell that has such
+// property set in tileset or in its layer. Returns 1 if
+// there is no tile with cost property in this cell, and
+// positive infinity if the cost is invalid, i.e. negative,
+// infinite or not a number.
+func (m *Map) MoveCost(x, y int) float64 {
+	cost, ok := m.cellCost(x, y)
+	if !ok {
+		return math.Inf(1)
+	}
+	return cost
+}
+
+// MinMoveCost returns the lowest move cost of all cells
+// that are not blocked, or 1 if all cells are blocked.
+// Cost is computed once and updated after tiles or layers
+// of the map are changed. Changes made directly in maps
+// with tile or layer properties require Reload.
+func (m *Map) MinMoveCost() float64 {
+	m.costs.mutex.Lock()
+	defer m.costs.mutex.Unlock()
+	if m.costs.valid {
+		return m.costs.cost
+	}
+	minCost := math.Inf(1)
+	for y := 0; y < int(m.tilescount.Y); y++ {
+		for x := 0; x < int(m.tilescount.X); x++ {
+			if m.Blocked(x, y) {
+				continue
+			}
+			minCost = math.Min(minCost, m.MoveCost(x, y))
+		}
+	}
+	if math.IsInf(minCost, 1) {
+		minCost = 1
+	}
+	m.costs.cost, m.costs.valid = minCost, true
+	return minCost
+}
+
+// cellCost returns cost of moving through map cell with
+// specified grid coordinates, false is returned if the
+// cost is invalid.
+func (m *Map) cellCost(x, y int) (float64, bool) {
+	for i := len(m.layers) - 1; i >= 0; i-- {
+		l := m.layers[i]
+		t := l.TileAt(x, y)
+		if t == nil {
+			continue
+		}
+		if cost, ok := floatProp(t.properties, CostProperty); ok {
+			return cost, validCost(cost)
+		}
+		if cost, ok := floatProp(l.properties, CostProperty); ok {
+			return cost, validCost(cost)
+		}
+	}
+	return 1, true
+}
+
+// resetMoveCost marks the lowest move cost as outdated.
+func (m *Map) resetMoveCost() {
+	m.costs.mutex.Lock()
+	defer m.costs.mutex.Unlock()
+	m.costs.valid = false
+}
+
+// blockingTile returns top blocking tile in map cell with
+// specified grid coordinates, or nil if cell is not blocked.
+func (m *Map) blockingTile(x, y int) *Tile {
+	for i := len(m.layers) - 1; i >= 0; i-- {
+		l := m.layers[i]
+		t := l.TileAt(x, y)
+		if t == nil {
+			continue
+		}
+		if boolProp(t.properties, CollisionProperty) ||
+			boolProp(l.properties, CollisionProperty) {
+			return t
+		}
+	}
+	return nil
+}
+
+// boolProp returns value of property with specified name as
+// bool, false is returned if there is no such property or
+// property value is not a valid bool.
+func boolProp(props map[string]string, name string) bool {
+	val, err := strconv.ParseBool(props[name])
+	return err == nil && val
+}
+
+// validCost checks if specified move cost is valid,
+// i.e. is not negative, infinite or not a number.
+func validCost(cost float64) bool {
+	return cost >= 0 && !math.IsInf(cost, 1)
+}
+
+// floatProp returns value of property with specified name
+// as float, false is returned if there is no such property
+// or property value is not a valid number.
+func floatProp(props map[string]string, name string) (float64, bool) {
+	val, err := strconv.ParseFloat(props[name], 64)
+	return val, err == nil
+}
@@ -0,0 +1,77 @@
+/*
+ * collision_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlocked(t *testing.T) {
+	m := testMap(t, ".#sn")
+	tests := []struct {
+		x, y    int
+		blocked bool
+	}{
+		{0, 0, false}, {1, 0, true}, {2, 0, false}, {3, 0, true},
+		{-1, 0, true}, {4, 0, true}, {0, 1, true},
+	}
+	for _, test := range tests {
+		if m.Blocked(test.x, test.y) != test.blocked {
+			t.Errorf("cell %d, %d: blocked: %v, expected: %v", test.x, test.y,
+				!test.blocked, test.blocked)
+		}
+	}
+}
+
+func TestMoveCost(t *testing.T) {
+	m := testMap(t, ".#sn")
+	if cost := m.MoveCost(0, 0); cost != 1 {
+		t.Errorf("default cost: %f, expected: 1", cost)
+	}
+	if cost := m.MoveCost(2, 0); cost != 5 {
+		t.Errorf("tile cost: %f, expected: 5", cost)
+	}
+	if cost := m.MoveCost(3, 0); !math.IsInf(cost, 1) {
+		t.Errorf("negative cost: %f, expected: +Inf", cost)
+	}
+}
+
+func TestMinMoveCost(t *testing.T) {
+	m := testMap(t, "ss#n")
+	if cost := m.MinMoveCost(); cost != 5 {
+		t.Fatalf("min cost: %f, expected: 5", cost)
+	}
+	err := m.Layers()[0].SetTile(1, 0, "tiles", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost := m.MinMoveCost(); cost != 1 {
+		t.Errorf("min cost after tile change: %f, expected: 1", cost)
+	}
+	m.Layers()[0].RemoveTile(1, 0)
+	if cost := m.MinMoveCost(); cost != 1 {
+		t.Errorf("min cost after tile removal: %f, expected: 1", cost)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * errors.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTilesetNotFound is returned when map tile refers to
+// tileset that is not present in the map. Tiles from TMX
+// data always refer to map tilesets, so NewMap never returns
+// this error, only Layer.SetTile and Autotiler do.
+var ErrTilesetNotFound = errors.New("tileset not found")
+
+// Struct for error returned when tileset image can't
+// be loaded. For images embedded in the map file path
+// is the path to the map file.
+type TilesetImageError struct {
+	Tileset string
+	Path    string
+	Err     error
+}
+
+// Struct for error returned when TMX attribute has
+// invalid color value.
+type InvalidColorError struct {
+	Attribute string
+	Value     string
+	Err       error
+}
+
+// Struct for error returned when map uses TMX feature
+// not supported by stone.
+type UnsupportedFeatureError struct {
+	Feature string
+}
+
+// Error returns error message.
+func (e *TilesetImageError) Error() string {
+	return fmt.Sprintf("tileset: %s: unable to load image: %s: %v", e.Tileset,
+		e.Path, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *TilesetImageError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns error message.
+func (e *InvalidColorError) Error() string {
+	return fmt.Sprintf("invalid color: %s: %s: %v", e.Attribute, e.Value, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *InvalidColorError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns error message.
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("unsupported feature: %s", e.Feature)
+}
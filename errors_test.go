@@ -0,0 +1,91 @@
+/*
+ * errors_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTilesetImageError(t *testing.T) {
+	path := writeTestMap(t, gridTMX("..", ".#"))
+	imgPath := filepath.Join(filepath.Dir(path), "tiles.png")
+	err := os.Remove(imgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewMap(path)
+	var imgErr *TilesetImageError
+	if !errors.As(err, &imgErr) {
+		t.Fatalf("error: %v, expected tileset image error", err)
+	}
+	if imgErr.Tileset != "tiles" || imgErr.Path != imgPath {
+		t.Errorf("tileset image error: %s, %s, expected: tiles, %s",
+			imgErr.Tileset, imgErr.Path, imgPath)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("error: %v, expected: %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestUnsupportedFeatureError(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmx     string
+		feature string
+	}{
+		{"zstd compression", strings.Replace(gridTMX("..", ".#"),
+			`<data encoding="csv">1,1,1,2</data>`,
+			`<data encoding="base64" compression="zstd">AAAA</data>`, 1),
+			"layer compression: zstd"},
+		{"external tileset", strings.Replace(gridTMX("..", ".#"), testTileset,
+			`<tileset firstgid="1" source="tiles.tsx"/>`, 1),
+			"external tileset: tiles.tsx"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewMap(writeTestMap(t, test.tmx))
+			var featureErr *UnsupportedFeatureError
+			if !errors.As(err, &featureErr) {
+				t.Fatalf("error: %v, expected unsupported feature error", err)
+			}
+			if featureErr.Feature != test.feature {
+				t.Errorf("feature: %s, expected: %s", featureErr.Feature,
+					test.feature)
+			}
+		})
+	}
+}
+
+func TestSetTileTilesetNotFound(t *testing.T) {
+	m := testMap(t, "..", ".#")
+	err := m.Layers()[0].SetTile(0, 0, "missing", 0)
+	if !errors.Is(err, ErrTilesetNotFound) {
+		t.Errorf("error: %v, expected: %v", err, ErrTilesetNotFound)
+	}
+}
@@ -26,6 +26,7 @@ package main
 
 import (
 	"fmt"
+	"image/color"
 
 	"golang.org/x/image/colornames"
 
@@ -57,13 +58,20 @@ func run() {
 	if err != nil {
 		panic(fmt.Errorf("Unable to create map: %v", err))
 	}
+	// Window clear color, map background color if set.
+	var clearColor color.Color = colornames.Black
+	if tmxMap.BackgroundColor() != nil {
+		clearColor = tmxMap.BackgroundColor()
+	}
+	// Create renderer for window.
+	renderer := stone.NewPixelRenderer(win)
 	// Main loop.
 	for !win.Closed() {
 		// Clear window.
-		win.Clear(colornames.Black)
+		win.Clear(clearColor)
 		// Draw map.
 		pos := pixel.V(0, 0) // e.g. camera pos
-		tmxMap.Draw(win, pixel.IM.Moved(pos))
+		tmxMap.Render(renderer, pixel.IM.Moved(pos))
 		// Update.
 		win.Update()
 	}
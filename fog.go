@@ -0,0 +1,145 @@
+/*
+ * fog.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"image/color"
+
+	"github.com/gopxl/pixel"
+)
+
+// Type for fog of war state of map cell.
+type FogState int
+
+const (
+	// Cell was never seen.
+	Unexplored FogState = iota
+	// Cell was seen before, but is not visible now.
+	Explored
+	// Cell is currently visible.
+	Visible
+)
+
+// Struct for fog of war over the map.
+type Fog struct {
+	m               *Map
+	width           int
+	states          []FogState
+	unexploredColor color.Color
+	exploredColor   color.Color
+}
+
+// NewFog creates new fog of war for specified map,
+// with all map cells unexplored.
+func NewFog(m *Map) *Fog {
+	f := new(Fog)
+	f.m = m
+	f.width = int(m.tilescount.X)
+	f.states = make([]FogState, f.width*int(m.tilescount.Y))
+	f.unexploredColor = pixel.RGBA{A: 1}
+	f.exploredColor = pixel.RGBA{A: 0.6}
+	return f
+}
+
+// Update computes field of view from specified map position
+// with specified radius and marks cells in this field as visible.
+// All cells visible before the update, but not in the current
+// field of view, are marked as explored.
+func (f *Fog) Update(pos pixel.Vec, radius int) {
+	fov := f.m.FOV(pos, radius)
+	for i, s := range f.states {
+		if fov.visible[i] {
+			f.states[i] = Visible
+			continue
+		}
+		if s == Visible {
+			f.states[i] = Explored
+		}
+	}
+}
+
+// Reset marks all map cells as unexplored.
+func (f *Fog) Reset() {
+	for i := range f.states {
+		f.states[i] = Unexplored
+	}
+}
+
+// State returns fog state of map cell with specified
+// grid coordinates.
+func (f *Fog) State(x, y int) FogState {
+	if !f.m.cellOnMap(x, y) {
+		return Unexplored
+	}
+	return f.states[y*f.width+x]
+}
+
+// SetUnexploredColor sets color of fog over unexplored cells.
+func (f *Fog) SetUnexploredColor(c color.Color) {
+	f.unexploredColor = c
+}
+
+// SetExploredColor sets color of fog over explored cells that
+// are not currently visible.
+func (f *Fog) SetExploredColor(c color.Color) {
+	f.exploredColor = c
+}
+
+// fit resizes fog to the current size of the map.
+// States of cells that are still on the map are kept.
+func (f *Fog) fit() {
+	width, height := int(f.m.tilescount.X), int(f.m.tilescount.Y)
+	if width == f.width && width*height == len(f.states) {
+		return
+	}
+	states := make([]FogState, width*height)
+	for i, s := range f.states {
+		x, y := i%f.width, i/f.width
+		if x < width && y < height {
+			states[y*width+x] = s
+		}
+	}
+	f.width = width
+	f.states = states
+}
+
+// render use specified matrix to draw fog over map cells with
+// renderer. If draw area is specified then fog is drawn only
+// over the cells inside this area of the renderer target.
+func (f *Fog) render(r Renderer, matrix pixel.Matrix, area *pixel.Rect) {
+	for i, s := range f.states {
+		if s == Visible {
+			continue
+		}
+		cellRect := drawRect(f.m.CellBounds(i%f.width, i/f.width), matrix)
+		if area != nil && !area.Intersects(cellRect) {
+			continue
+		}
+		c := f.unexploredColor
+		if s == Explored {
+			c = f.exploredColor
+		}
+		r.FillRect(cellRect, c)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * fov.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel"
+)
+
+// Multipliers for transforming quadrant row and column
+// to grid coordinates, for each of four field of view
+// quadrants.
+var fovQuadrants = [4][4]int{
+	{1, 0, 0, -1},
+	{1, 0, 0, 1},
+	{0, 1, 1, 0},
+	{0, -1, 1, 0},
+}
+
+// Struct for field of view on the map.
+type FOV struct {
+	m       *Map
+	width   int
+	visible []bool
+}
+
+// Struct for single row of field of view quadrant scan.
+type fovRow struct {
+	depth      int
+	start, end float64
+}
+
+// FOV computes field of view from specified map position,
+// with symmetric shadowcasting. Blocked map cells stop the
+// sight, but are visible themselves. Sight between two open
+// cells is always mutual. Radius specifies maximal sight
+// distance in map cells.
+func (m *Map) FOV(pos pixel.Vec, radius int) *FOV {
+	f := new(FOV)
+	f.m = m
+	f.width = int(m.tilescount.X)
+	f.visible = make([]bool, f.width*int(m.tilescount.Y))
+	x, y, ok := m.Cell(pos)
+	if !ok {
+		return f
+	}
+	f.setVisible(x, y)
+	for _, q := range fovQuadrants {
+		f.scan(x, y, fovRow{1, -1, 1}, radius, q)
+	}
+	return f
+}
+
+// Visible checks if map cell with specified grid
+// coordinates is in field of view.
+func (f *FOV) Visible(x, y int) bool {
+	if !f.m.cellOnMap(x, y) {
+		return false
+	}
+	return f.visible[y*f.width+x]
+}
+
+// scan scans specified row of specified quadrant, and all
+// rows behind it, and marks all cells that are not in
+// shadow as visible. Open cells are marked only if they
+// are in the row slopes, to keep the sight symmetric.
+func (f *FOV) scan(cx, cy int, row fovRow, radius int, quadrant [4]int) {
+	if row.depth > radius {
+		return
+	}
+	d := float64(row.depth)
+	minCol := int(math.Floor(d*row.start + 0.5))
+	maxCol := int(math.Ceil(d*row.end - 0.5))
+	scanned, prevBlocked := false, false
+	for col := minCol; col <= maxCol; col++ {
+		x := cx + col*quadrant[0] + row.depth*quadrant[1]
+		y := cy + col*quadrant[2] + row.depth*quadrant[3]
+		blocked := f.m.Blocked(x, y)
+		symmetric := float64(col) >= d*row.start &&
+			float64(col) <= d*row.end
+		if (blocked || symmetric) &&
+			col*col+row.depth*row.depth <= radius*radius {
+			f.setVisible(x, y)
+		}
+		slope := float64(2*col-1) / (2 * d)
+		if scanned && prevBlocked && !blocked {
+			row.start = slope
+		}
+		if scanned && !prevBlocked && blocked {
+			next := fovRow{row.depth + 1, row.start, slope}
+			f.scan(cx, cy, next, radius, quadrant)
+		}
+		scanned, prevBlocked = true, blocked
+	}
+	if scanned && !prevBlocked {
+		f.scan(cx, cy, fovRow{row.depth + 1, row.start, row.end}, radius,
+			quadrant)
+	}
+}
+
+// setVisible marks map cell with specified grid coordinates
+// as visible.
+func (f *FOV) setVisible(x, y int) {
+	if f.m.cellOnMap(x, y) {
+		f.visible[y*f.width+x] = true
+	}
+}
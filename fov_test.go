@@ -0,0 +1,92 @@
+/*
+ * fov_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+func TestFOV(t *testing.T) {
+	m := testMap(t,
+		".....",
+		".....",
+		"..#..",
+		".....",
+		".....",
+	)
+	f := m.FOV(m.mapPos(pixel.V(0.5, 2.5)), 10)
+	tests := []struct {
+		x, y    int
+		visible bool
+	}{
+		{0, 2, true}, {1, 2, true}, {2, 2, true}, {3, 2, false},
+		{4, 2, false}, {4, 0, true}, {4, 4, true}, {-1, 2, false},
+	}
+	for _, test := range tests {
+		if f.Visible(test.x, test.y) != test.visible {
+			t.Errorf("cell %d, %d: visible: %v, expected: %v", test.x, test.y,
+				!test.visible, test.visible)
+		}
+	}
+}
+
+func TestFOVRadius(t *testing.T) {
+	m := testMap(t, ".....")
+	f := m.FOV(m.mapPos(pixel.V(0.5, 0.5)), 2)
+	for x := 0; x < 5; x++ {
+		if f.Visible(x, 0) != (x <= 2) {
+			t.Errorf("cell %d, 0: visible: %v, expected: %v", x, !(x <= 2),
+				x <= 2)
+		}
+	}
+}
+
+func TestFOVSymmetry(t *testing.T) {
+	rows := []string{
+		".....",
+		".#...",
+		"...#.",
+		"#....",
+		"..#..",
+	}
+	m := testMap(t, rows...)
+	fovs := make(map[[2]int]*FOV)
+	for y, r := range rows {
+		for x, c := range r {
+			if c == '.' {
+				fovs[[2]int{x, y}] = m.FOV(m.mapPos(pixel.V(float64(x)+0.5,
+					float64(y)+0.5)), 10)
+			}
+		}
+	}
+	for a, fa := range fovs {
+		for b, fb := range fovs {
+			if fa.Visible(b[0], b[1]) != fb.Visible(a[0], a[1]) {
+				t.Errorf("asymmetric sight between cells %v and %v", a, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * imagerenderer.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for renderer that draws map on RGBA image.
+// Renderer does not require graphic context, so it can be
+// used to render map without window.
+type ImageRenderer struct {
+	img    *image.RGBA
+	images map[pixel.Picture]*image.RGBA
+	smooth bool
+}
+
+// NewImageRenderer creates new renderer for specified image.
+// Bottom-left corner of the image is at (0, 0) target position.
+func NewImageRenderer(img *image.RGBA) *ImageRenderer {
+	r := new(ImageRenderer)
+	r.img = img
+	r.images = make(map[pixel.Picture]*image.RGBA)
+	return r
+}
+
+// SetSmooth enables smoothing of scaled and rotated tiles.
+func (r *ImageRenderer) SetSmooth(smooth bool) {
+	r.smooth = smooth
+}
+
+// Smooth checks if smoothing of tiles is enabled.
+func (r *ImageRenderer) Smooth() bool {
+	return r.smooth
+}
+
+// Bounds returns bounds of renderer image on target.
+func (r *ImageRenderer) Bounds() pixel.Rect {
+	return pixel.R(0, 0, float64(r.img.Bounds().Dx()), float64(r.img.Bounds().Dy()))
+}
+
+// Image returns renderer image.
+func (r *ImageRenderer) Image() *image.RGBA {
+	return r.img
+}
+
+// DrawTile draws tile on renderer image.
+func (r *ImageRenderer) DrawTile(t *Tile, matrix pixel.Matrix) {
+	src := r.pictureImage(t.Picture())
+	// Pictures have Y axis inverted in relation to images.
+	picY := t.Picture().Bounds().Min.Y + t.Picture().Bounds().Max.Y
+	frame := t.Frame()
+	srcRect := image.Rect(int(frame.Min.X), int(picY-frame.Max.Y),
+		int(frame.Max.X), int(picY-frame.Min.Y))
+	// Source image position to frame position.
+	center := frame.Center()
+	srcMatrix := pixel.Matrix{1, 0, 0, -1, -center.X, picY - center.Y}
+	// Frame position to target position and then to image position.
+	srcMatrix = srcMatrix.Chained(matrix).Chained(r.imageMatrix())
+	aff := f64.Aff3{srcMatrix[0], srcMatrix[2], srcMatrix[4],
+		srcMatrix[1], srcMatrix[3], srcMatrix[5]}
+	if aff[0] == 1 && aff[1] == 0 && aff[3] == 0 && aff[4] == 1 &&
+		aff[2] == math.Trunc(aff[2]) && aff[5] == math.Trunc(aff[5]) {
+		// Transform simplifies translations to copy with invalid
+		// destination point, so copy is used directly.
+		dp := srcRect.Min.Add(image.Pt(int(aff[2]), int(aff[5])))
+		draw.Copy(r.img, dp, src, srcRect, draw.Over, nil)
+		return
+	}
+	if r.smooth {
+		draw.BiLinear.Transform(r.img, aff, src, srcRect, draw.Over, nil)
+		return
+	}
+	draw.NearestNeighbor.Transform(r.img, aff, src, srcRect, draw.Over, nil)
+}
+
+// FillRect fills specified area of renderer image with
+// specified color.
+func (r *ImageRenderer) FillRect(rect pixel.Rect, c color.Color) {
+	min := r.imageMatrix().Project(rect.Min)
+	max := r.imageMatrix().Project(rect.Max)
+	imgRect := image.Rect(int(math.Round(min.X)), int(math.Round(min.Y)),
+		int(math.Round(max.X)), int(math.Round(max.Y)))
+	draw.Draw(r.img, imgRect, image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// Flush does nothing, as image renderer draws everything
+// immediately.
+func (r *ImageRenderer) Flush() {
+}
+
+// imageMatrix returns matrix that translates target
+// positions to image positions.
+func (r *ImageRenderer) imageMatrix() pixel.Matrix {
+	bounds := r.img.Bounds()
+	return pixel.Matrix{1, 0, 0, -1, float64(bounds.Min.X), float64(bounds.Max.Y)}
+}
+
+// pictureImage returns image for specified picture.
+func (r *ImageRenderer) pictureImage(pic pixel.Picture) *image.RGBA {
+	img := r.images[pic]
+	if img == nil {
+		img = pixel.PictureDataFromPicture(pic).Image()
+		r.images[pic] = img
+	}
+	return img
+}
+
+// DrawImage draws specified area of the map on new image,
+// scaled by specified scale. Only specified layers are drawn,
+// or all map layers if there is no layers specified.
+func (m *Map) DrawImage(area pixel.Rect, scale float64, layers ...*Layer) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(area.W()*scale)),
+		int(math.Ceil(area.H()*scale))))
+	if len(layers) < 1 {
+		layers = m.layers
+	}
+	matrix := pixel.IM.Moved(area.Min).Scaled(pixel.ZV, scale)
+	drawArea := pixel.R(0, 0, float64(img.Bounds().Dx()),
+		float64(img.Bounds().Dy()))
+	r := NewImageRenderer(img)
+	r.SetSmooth(m.opts.smooth)
+	m.render(r, matrix, &drawArea, layers)
+	return img
+}
+
+// DrawBounds returns bounds of the map area covered
+// by drawn tiles, including tiles larger than map cells.
+func (m *Map) DrawBounds() pixel.Rect {
+	bounds := pixel.ZR
+	for _, l := range m.layers {
+		if l.image != nil && !l.repeatX && !l.repeatY {
+			if bounds == pixel.ZR {
+				bounds = l.image.bounds
+			}
+			bounds = bounds.Union(l.image.bounds)
+		}
+		for _, t := range l.tiles {
+			if bounds == pixel.ZR {
+				bounds = t.bounds
+				continue
+			}
+			bounds = bounds.Union(t.bounds)
+		}
+	}
+	return bounds
+}
@@ -0,0 +1,96 @@
+/*
+ * testgrid.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Package testgrid provides the grid map fixture shared by
+// the stone and pathfinding tests.
+package testgrid
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Tiles for test map grid: floor, wall, tile with cost 5
+// and tile with invalid negative cost.
+var Tiles = map[rune]int{'.': 1, '#': 2, 's': 3, 'n': 4}
+
+// Dir returns path to the directory with grid fixture files.
+func Dir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "testdata", "grid")
+}
+
+// Tileset returns TMX tileset for test map grid.
+// Panics if the fixture file can't be read.
+func Tileset() string {
+	data, err := os.ReadFile(filepath.Join(Dir(), "tileset.xml"))
+	if err != nil {
+		panic(fmt.Sprintf("unable to read grid tileset: %v", err))
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// TMX returns TMX data of map with single layer with
+// cells from specified grid rows.
+func TMX(rows ...string) string {
+	var data []string
+	for _, r := range rows {
+		for _, c := range r {
+			data = append(data, fmt.Sprint(Tiles[c]))
+		}
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="%d" height="%d" tilewidth="32" tileheight="32">
+ %s
+ <layer id="1" name="ground" width="%d" height="%d">
+  <data encoding="csv">%s</data>
+ </layer>
+</map>`, len(rows[0]), len(rows), Tileset(), len(rows[0]), len(rows),
+		strings.Join(data, ","))
+}
+
+// WriteMap writes specified TMX data to map file in
+// temporary directory with test tileset image. Returns path
+// to the map file.
+func WriteMap(t testing.TB, tmx string) string {
+	t.Helper()
+	dir := t.TempDir()
+	img, err := os.ReadFile(filepath.Join(Dir(), "tiles.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "tiles.png"), img, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "map.tmx")
+	err = os.WriteFile(path, []byte(tmx), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
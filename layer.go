@@ -25,54 +25,188 @@ package stone
 
 import (
 	"fmt"
-	
+	"sort"
+
 	"github.com/salviati/go-tmx/tmx"
 
 	"github.com/gopxl/pixel"
 )
 
-// Struct for map layer.
+// Struct for map layer. Layer is a tile layer or an image
+// layer, with single image and without tiles.
 type Layer struct {
-	name    string
-	tiles   []*Tile
+	areaMap    *Map
+	name       string
+	tiles      []*Tile
+	grid       []*Tile
+	width      int
+	properties map[string]string
+	parallax   pixel.Vec
+	image      *Tile
+	imagePath  string
+	repeatX    bool
+	repeatY    bool
 }
 
 // newLayer creates new layer with tiles for specified map.
 func newLayer(m *Map, tmxLayer tmx.Layer) (*Layer, error) {
-	l := new(Layer)
-	l.name = tmxLayer.Name
-	l.tiles = make([]*Tile, 0)
-	var tileX, tileY float64
-	for _, dt := range tmxLayer.DecodedTiles {
+	l := emptyLayer(m, tmxLayer.Name)
+	l.properties = properties(tmxLayer.Properties)
+	for i, dt := range tmxLayer.DecodedTiles {
 		tileset := dt.Tileset
-		if tileset != nil {
-			tilesetPic := m.tilesets[tileset.Name]
-			if tilesetPic == nil {
-				return nil, fmt.Errorf("unable to found tileset source: %s",
-					tileset.Name)
+		if tileset != nil && i < len(l.grid) {
+			tile, err := m.cellTile(tileset.Name, dt.ID, i%l.width, i/l.width)
+			if err != nil {
+				return nil, err
 			}
-			tileBounds := m.tileBounds(tilesetPic, dt.ID)
-			pic := pixel.NewSprite(tilesetPic, tileBounds)
-			tilePos := pixel.V(m.tilesize.X*tileX, m.tilesize.Y*tileY)
-			tilePos.Y = m.Size().Y - tilePos.Y
-			tile := newTile(pic, tilePos)
-			l.tiles = append(l.tiles, tile)		
-		}
-		tileX++
-		if tileX > m.tilescount.X-1 {
-			tileX = 0
-			tileY++
+			tile.setFlips(dt.HorizontalFlip, dt.VerticalFlip, dt.DiagonalFlip)
+			l.tiles = append(l.tiles, tile)
+			l.grid[i] = tile
 		}
 	}
+	// Tiles are drawn in map render order.
+	sort.SliceStable(l.tiles, func(i, j int) bool {
+		return l.tiles[i].drawIndex < l.tiles[j].drawIndex
+	})
 	return l, nil
 }
 
+// newImageLayer creates new image layer for specified map,
+// with specified picture loaded from file with specified path.
+// Layer without picture has no image.
+func newImageLayer(m *Map, tmxLayer tmxImageLayer, pic pixel.Picture, path string) *Layer {
+	l := emptyLayer(m, tmxLayer.Name)
+	l.properties = properties(tmxLayer.Properties)
+	l.parallax = tmxLayer.parallax()
+	l.repeatX = tmxLayer.RepeatX != 0
+	l.repeatY = tmxLayer.RepeatY != 0
+	l.imagePath = path
+	if pic != nil {
+		// Image is placed with the top-left corner on layer offset.
+		size := pic.Bounds().Size()
+		pos := m.tmxPos(pixel.V(tmxLayer.OffsetX, tmxLayer.OffsetY+size.Y))
+		l.image = newTile(pic, pic.Bounds(), pos)
+	}
+	return l
+}
+
+// emptyLayer creates new layer without tiles for specified map.
+func emptyLayer(m *Map, name string) *Layer {
+	l := new(Layer)
+	l.areaMap = m
+	l.name = name
+	l.tiles = make([]*Tile, 0)
+	l.width = int(m.tilescount.X)
+	l.grid = make([]*Tile, l.width*int(m.tilescount.Y))
+	l.properties = make(map[string]string)
+	l.parallax = pixel.V(1, 1)
+	return l
+}
+
 // Name returns layer name from tmx data.
 func (l *Layer) Name() string {
 	return l.name
 }
 
-// Tiles returns all layer tiles.
+// Image returns tile with picture of image layer, or nil
+// if layer is not an image layer or has no image.
+func (l *Layer) Image() *Tile {
+	return l.image
+}
+
+// RepeatX checks if image of image layer is repeated along
+// X axis.
+func (l *Layer) RepeatX() bool {
+	return l.repeatX
+}
+
+// RepeatY checks if image of image layer is repeated along
+// Y axis.
+func (l *Layer) RepeatY() bool {
+	return l.repeatY
+}
+
+// Tiles returns all layer tiles, in map render order.
 func (l *Layer) Tiles() []*Tile {
 	return l.tiles
 }
+
+// TileAt returns layer tile in map cell with specified
+// grid coordinates, or nil if there is no tile in this cell.
+func (l *Layer) TileAt(x, y int) *Tile {
+	if x < 0 || y < 0 || x >= l.width || y*l.width+x >= len(l.grid) {
+		return nil
+	}
+	return l.grid[y*l.width+x]
+}
+
+// Properties returns layer properties from tmx data.
+func (l *Layer) Properties() map[string]string {
+	return l.properties
+}
+
+// Parallax returns layer parallax scrolling factor.
+// Layer with factor 1 moves with the map, layers with
+// lower factors scroll slower and with higher factors
+// faster than the map.
+func (l *Layer) Parallax() pixel.Vec {
+	return l.parallax
+}
+
+// SetParallax sets layer parallax scrolling factor.
+func (l *Layer) SetParallax(parallax pixel.Vec) {
+	l.parallax = parallax
+}
+
+// drawMatrix returns matrix for drawing layer with
+// specified map draw matrix, moved by parallax offset
+// for specified map position in the center of the view.
+func (l *Layer) drawMatrix(matrix pixel.Matrix, viewCenter pixel.Vec) pixel.Matrix {
+	if l.parallax == pixel.V(1, 1) {
+		return matrix
+	}
+	origin := l.areaMap.ParallaxOrigin()
+	offset := viewCenter.Sub(origin).ScaledXY(pixel.V(1-l.parallax.X, 1-l.parallax.Y))
+	matrix[4] -= offset.X * matrix[0]
+	matrix[5] -= offset.Y * matrix[0]
+	return matrix
+}
+
+// SetTile sets tile with specified ID from tileset with
+// specified name in layer cell with specified grid coordinates.
+func (l *Layer) SetTile(x, y int, tileset string, id int) error {
+	if !l.areaMap.cellOnMap(x, y) {
+		return fmt.Errorf("cell outside map: %d, %d", x, y)
+	}
+	tile, err := l.areaMap.cellTile(tileset, tmx.ID(id), x, y)
+	if err != nil {
+		return err
+	}
+	l.RemoveTile(x, y)
+	l.grid[y*l.width+x] = tile
+	i := sort.Search(len(l.tiles), func(i int) bool {
+		return l.tiles[i].drawIndex > tile.drawIndex
+	})
+	l.tiles = append(l.tiles, nil)
+	copy(l.tiles[i+1:], l.tiles[i:])
+	l.tiles[i] = tile
+	l.areaMap.resetMoveCost()
+	return nil
+}
+
+// RemoveTile removes tile from layer cell with specified
+// grid coordinates.
+func (l *Layer) RemoveTile(x, y int) {
+	tile := l.TileAt(x, y)
+	if tile == nil {
+		return
+	}
+	l.grid[y*l.width+x] = nil
+	l.areaMap.resetMoveCost()
+	for i, t := range l.tiles {
+		if t == tile {
+			l.tiles = append(l.tiles[:i], l.tiles[i+1:]...)
+			break
+		}
+	}
+}
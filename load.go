@@ -0,0 +1,437 @@
+/*
+ * load.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/salviati/go-tmx/tmx"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for map loading progress.
+type Progress struct {
+	// Number of loaded map elements: tileset images,
+	// layers and object layers.
+	Done int
+	// Total number of map elements to load.
+	Total int
+}
+
+// Struct for map loader.
+type loader struct {
+	ctx      context.Context
+	progress func(Progress)
+	done     int
+	total    int
+}
+
+// Struct for tileset image to load.
+type tilesetImage struct {
+	tileset    int
+	tile       tmx.ID
+	collection bool
+	path       string
+	data       *tmxImageData
+}
+
+// Struct for result of tileset picture loading.
+type pictureResult struct {
+	index   int
+	picture pixel.Picture
+	cached  *cachedPicture
+	err     error
+}
+
+// NewMapContext creates new map from .tmx file with specified
+// path and options. Tileset pictures are decoded concurrently.
+// Loading stops when specified context is done, and context
+// error is returned. Map creation does not use graphic context,
+// so the map can be loaded outside the main thread, GPU
+// resources are created on the first draw.
+func NewMapContext(ctx context.Context, path string, opts ...Option) (*Map, error) {
+	return loadMap(ctx, path, newOptions(opts))
+}
+
+// loadMap loads map from .tmx file with specified path
+// and options.
+func loadMap(ctx context.Context, path string, opts *options) (*Map, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	tmxMap, tmxData, err := tmxMap(opts.fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retive TMX map: %w", err)
+	}
+	m := newMap(tmxMap, opts)
+	m.path = path
+	m.order = tmxData.RenderOrder
+	m.parallaxOrigin = m.tmxPos(pixel.V(tmxData.ParallaxX, tmxData.ParallaxY))
+	if len(tmxData.Background) > 0 {
+		m.background, err = parseColor(tmxData.Background)
+		if err != nil {
+			return nil, &InvalidColorError{"backgroundcolor", tmxData.Background, err}
+		}
+	}
+	images := m.tilesetImages(tmxData)
+	l := &loader{ctx: ctx, progress: opts.progress}
+	l.total = len(images)
+	for _, tl := range tmxMap.Layers {
+		if opts.loadLayer(tl.Name) {
+			l.total++
+		}
+	}
+	for _, il := range tmxData.ImageLayers {
+		if opts.loadLayer(il.Name) {
+			l.total++
+		}
+	}
+	for _, og := range tmxMap.ObjectGroups {
+		if opts.loadLayer(og.Name) {
+			l.total++
+		}
+	}
+	err = m.loadTilesets(l, images)
+	if err != nil {
+		m.Release()
+		return nil, err
+	}
+	err = m.build(l, tmxData)
+	if err != nil {
+		m.Release()
+		return nil, err
+	}
+	return m, nil
+}
+
+// step marks next map element as loaded and reports
+// loading progress.
+func (l *loader) step() {
+	l.done++
+	if l.progress != nil {
+		l.progress(Progress{l.done, l.total})
+	}
+}
+
+// newMap creates new map without tilesets and layers for
+// specified TMX map.
+func newMap(tmxMap *tmx.Map, opts *options) *Map {
+	m := new(Map)
+	m.tmxMap = tmxMap
+	m.opts = opts
+	m.cache = opts.cache
+	m.tilesize = pixel.V(float64(m.tmxMap.TileWidth),
+		float64(m.tmxMap.TileHeight))
+	m.tilescount = pixel.V(float64(m.tmxMap.Width),
+		float64(m.tmxMap.Height))
+	m.mapsize = pixel.V(float64(int(m.tilesize.X*m.tilescount.X)),
+		float64(int(m.tilesize.Y*m.tilescount.Y)))
+	m.tileProps = make(map[tileKey]map[string]string)
+	m.costs = new(moveCostCache)
+	return m
+}
+
+// loadTilesets concurrently loads pictures for all map
+// tilesets. Pictures embedded in the map file, also pictures
+// of image collection tiles, are decoded from TMX data and
+// not stored in the resource cache.
+func (m *Map) loadTilesets(l *loader, images []tilesetImage) error {
+	results := make(chan pictureResult, len(images))
+	workers := make(chan struct{}, runtime.NumCPU())
+	for i, img := range images {
+		go func(i int, img tilesetImage) {
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			res := pictureResult{index: i}
+			res.err = l.ctx.Err()
+			if res.err == nil && img.data != nil {
+				res.picture, res.err = m.embeddedPicture(img.data)
+			} else if res.err == nil {
+				res.picture, res.cached, res.err = m.loadPicture(img.path)
+			}
+			results <- res
+		}(i, img)
+	}
+	pics := make([]pixel.Picture, len(images))
+	errs := make([]error, len(images))
+	for received := 0; received < len(images); received++ {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				errs[res.index] = res.err
+				continue
+			}
+			if res.cached != nil {
+				m.cached = append(m.cached, res.cached)
+			}
+			pics[res.index] = res.picture
+			l.step()
+		case <-l.ctx.Done():
+			go m.releaseResults(results, len(images)-received)
+			return l.ctx.Err()
+		}
+	}
+	tilesets := make([]*Tileset, len(m.tmxMap.Tilesets))
+	for i, img := range images {
+		ts := m.tmxMap.Tilesets[img.tileset]
+		path := img.path
+		if img.data != nil {
+			path = ""
+		}
+		if errs[i] != nil && img.data != nil {
+			return &TilesetImageError{ts.Name, m.path, errs[i]}
+		}
+		if errs[i] != nil {
+			return &TilesetImageError{ts.Name, path, errs[i]}
+		}
+		if !img.collection {
+			tilesets[img.tileset] = newTileset(ts, pics[i], path)
+			continue
+		}
+		if tilesets[img.tileset] == nil {
+			tilesets[img.tileset] = newCollectionTileset(ts)
+		}
+		tilesets[img.tileset].addTile(img.tile, pics[i], path)
+	}
+	m.tilesets = append(m.tilesets, tilesets...)
+	return nil
+}
+
+// tilesetImages returns all images of map tilesets, with
+// separate image for each tile of image collection tilesets.
+func (m *Map) tilesetImages(tmxData *tmxData) []tilesetImage {
+	var images []tilesetImage
+	for i, ts := range m.tmxMap.Tilesets {
+		var data *tmxTileset
+		if i < len(tmxData.Tilesets) {
+			data = &tmxData.Tilesets[i]
+		}
+		img := tilesetImage{tileset: i, path: m.resourcePath(ts.Image.Source)}
+		if data != nil {
+			img.data = data.Image.Data
+		}
+		if len(ts.Image.Source) > 0 || img.data != nil {
+			images = append(images, img)
+			continue
+		}
+		var embedded map[tmx.ID]*tmxImageData
+		if data != nil {
+			embedded = data.tileImages()
+		}
+		count := len(images)
+		for _, t := range ts.Tiles {
+			img := tilesetImage{tileset: i, tile: t.ID, collection: true,
+				path: m.resourcePath(t.Image.Source), data: embedded[t.ID]}
+			if len(t.Image.Source) > 0 || img.data != nil {
+				images = append(images, img)
+			}
+		}
+		if len(images) == count {
+			// Tileset without any image.
+			images = append(images, img)
+		}
+	}
+	return images
+}
+
+// releaseResults waits for specified number of results
+// from specified channel and releases loaded pictures
+// from map resource cache.
+func (m *Map) releaseResults(results chan pictureResult, count int) {
+	for i := 0; i < count; i++ {
+		res := <-results
+		if res.cached != nil {
+			m.cache.release(res.cached)
+		}
+	}
+}
+
+// build creates map Wang sets, layers and object layers
+// from TMX data.
+func (m *Map) build(l *loader, tmxData *tmxData) error {
+	// Tilesets offsets, tiles properties and Wang sets.
+	for i, ts := range tmxData.Tilesets {
+		if i < len(m.tilesets) {
+			m.tilesets[i].setTMXData(ts)
+		}
+		for _, t := range ts.Tiles {
+			m.tileProps[tileKey{ts.Name, t.ID}] = properties(t.Properties)
+		}
+		for _, tmxSet := range ts.WangSets {
+			ws, err := newWangSet(ts.Name, tmxSet)
+			if err != nil {
+				return fmt.Errorf("unable to create Wang set: %s: %w",
+					tmxSet.Name, err)
+			}
+			m.wangSets = append(m.wangSets, ws)
+		}
+	}
+	// Map tile and image layers, in TMX document order.
+	tileLayers, imageLayers := 0, 0
+	for _, element := range tmxData.layerOrder {
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+		var name string
+		var layer *Layer
+		var err error
+		switch element {
+		case "layer":
+			tl := m.tmxMap.Layers[tileLayers]
+			name = tl.Name
+			if m.opts.loadLayer(name) {
+				layer, err = newLayer(m, tl)
+				if err == nil {
+					layer.parallax = tmxData.Layers[tileLayers].parallax()
+				}
+			}
+			tileLayers++
+		case "imagelayer":
+			il := tmxData.ImageLayers[imageLayers]
+			name = il.Name
+			if m.opts.loadLayer(name) {
+				layer, err = m.imageLayer(il)
+			}
+			imageLayers++
+		}
+		if err != nil {
+			return fmt.Errorf("unable to create layer: %s: %w", name, err)
+		}
+		if layer == nil {
+			continue
+		}
+		m.layers = append(m.layers, layer)
+		l.step()
+	}
+	// Object layers.
+	for i, og := range m.tmxMap.ObjectGroups {
+		if !m.opts.loadLayer(og.Name) {
+			continue
+		}
+		if err := l.ctx.Err(); err != nil {
+			return err
+		}
+		var data tmxObjectGroup
+		if i < len(tmxData.ObjectGroups) {
+			data = tmxData.ObjectGroups[i]
+		}
+		objLayer, err := newObjectLayer(m, og, data)
+		if err != nil {
+			return fmt.Errorf("unable to create object layer: %s: %w",
+				og.Name, err)
+		}
+		m.objLayers = append(m.objLayers, objLayer)
+		l.step()
+	}
+	return nil
+}
+
+// imageLayer creates new image layer from specified TMX
+// image layer, with picture loaded from file or decoded
+// from data embedded in the map file.
+func (m *Map) imageLayer(tmxLayer tmxImageLayer) (*Layer, error) {
+	img := tmxLayer.Image
+	if img.Data != nil {
+		pic, err := m.embeddedPicture(img.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load image: %s: %w", m.path, err)
+		}
+		return newImageLayer(m, tmxLayer, pic, ""), nil
+	}
+	if len(img.Source) < 1 {
+		return newImageLayer(m, tmxLayer, nil, ""), nil
+	}
+	path := m.resourcePath(img.Source)
+	pic, cp, err := m.loadPicture(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load image: %s: %w", path, err)
+	}
+	if cp != nil {
+		m.cached = append(m.cached, cp)
+	}
+	return newImageLayer(m, tmxLayer, pic, path), nil
+}
+
+// loadPicture loads picture from file with specified path,
+// from resource cache if map has one. Returns also cache entry
+// with the picture that must be released by the map.
+func (m *Map) loadPicture(path string) (pixel.Picture, *cachedPicture, error) {
+	if m.cache == nil {
+		pic, err := m.picture(path)
+		return pic, nil, err
+	}
+	cp, err := m.cache.picture(m.cacheKey(path), path, m.picture)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cp.picture, cp, nil
+}
+
+// picture loads picture from file with specified path, with
+// map file system and image loader.
+func (m *Map) picture(path string) (pixel.Picture, error) {
+	return picture(m.opts.fsys, path, m.opts.imageLoader)
+}
+
+// embeddedPicture decodes picture from specified image data
+// embedded in the map file, with map image loader. Loader
+// receives path to the map file.
+func (m *Map) embeddedPicture(data *tmxImageData) (pixel.Picture, error) {
+	img, err := data.decode()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read embedded image: %w", err)
+	}
+	return readPicture(m.path, bytes.NewReader(img), m.opts.imageLoader)
+}
+
+// cacheKey returns resource cache key for file with specified
+// path, in map file system and loaded with map image loader.
+func (m *Map) cacheKey(path string) cacheKey {
+	return newCacheKey(m.opts.fsysID, m.opts.imageLoaderID, path)
+}
+
+// resourcePath returns path to map resource file with
+// specified source path, relative to the map file.
+func (m *Map) resourcePath(source string) string {
+	return resourcePath(m.opts.fsys, m.path, source)
+}
+
+// resourcePath returns path to resource file with specified
+// source path, relative to map file with specified path in
+// specified file system, or in OS file system if there is
+// no file system specified.
+func resourcePath(fsys fs.FS, mapPath, source string) string {
+	if fsys != nil {
+		return path.Join(path.Dir(mapPath), source)
+	}
+	return filepath.Join(filepath.Dir(mapPath), filepath.FromSlash(source))
+}
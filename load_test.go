@@ -0,0 +1,162 @@
+/*
+ * load_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadTilesets is TMX data of additional tilesets with
+// images from files other than the test tileset image.
+const loadTilesets = `<tileset firstgid="5" name="a" tilewidth="32" tileheight="32" tilecount="4" columns="4">
+  <image source="a.png" width="128" height="32"/>
+ </tileset>
+ <tileset firstgid="9" name="b" tilewidth="32" tileheight="32" tilecount="4" columns="4">
+  <image source="b.png" width="128" height="32"/>
+ </tileset>
+ <objectgroup id="2" name="objects">
+  <object id="1" name="chest" x="0" y="0" width="32" height="32"/>
+ </objectgroup>`
+
+// writeLoadMap writes test map with three tilesets, tile layer
+// and object layer. Returns paths to the map file and to all
+// tileset images.
+func writeLoadMap(t *testing.T) (string, []string) {
+	t.Helper()
+	tmx := strings.Replace(gridTMX("..", ".#"), "</map>", loadTilesets+"\n</map>", 1)
+	path := writeTestMap(t, tmx)
+	dir := filepath.Dir(path)
+	images := []string{filepath.Join(dir, "tiles.png")}
+	for _, name := range []string{"a.png", "b.png"} {
+		images = append(images, filepath.Join(dir, name))
+		writeTestImage(t, filepath.Join(dir, name), 128, 32, color.White)
+	}
+	return path, images
+}
+
+func TestProgress(t *testing.T) {
+	path, _ := writeLoadMap(t)
+	var progress []Progress
+	_, err := NewMap(path, WithProgress(func(p Progress) {
+		progress = append(progress, p)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Three tileset images, tile layer and object layer.
+	var expected []Progress
+	for i := 1; i <= 5; i++ {
+		expected = append(expected, Progress{i, 5})
+	}
+	if fmt.Sprint(progress) != fmt.Sprint(expected) {
+		t.Errorf("progress: %v, expected: %v", progress, expected)
+	}
+}
+
+func TestLoadCancel(t *testing.T) {
+	path, images := writeLoadMap(t)
+	cache := NewResourceCache()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	release := make(chan struct{})
+	loader := ImageLoaderFunc(func(path string, r io.Reader) (image.Image, error) {
+		cancel()
+		<-release
+		return image.NewRGBA(image.Rect(0, 0, 128, 32)), nil
+	})
+	_, err := NewMapContext(ctx, path, WithResourceCache(cache),
+		WithImageLoader(loader))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error: %v, expected: %v", err, context.Canceled)
+	}
+	// Pictures loaded after cancellation are released
+	// in background.
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("cache not empty after cancellation: %d", cache.Len())
+	}
+	for _, img := range images {
+		if refs := cache.References(img); refs != 0 {
+			t.Errorf("references of %s: %d, expected: 0", img, refs)
+		}
+	}
+}
+
+func TestLayerFilter(t *testing.T) {
+	// Filtered image layer with missing image, that would fail
+	// map loading if it was loaded.
+	tmx := strings.Replace(gridTMX("..", ".#"), "</map>", `<imagelayer id="2" name="sky">
+  <image source="missing.png" width="64" height="64"/>
+ </imagelayer>
+ <layer id="3" name="top" width="2" height="2">
+  <data encoding="csv">0,0,0,1</data>
+ </layer>
+ <objectgroup id="4" name="objects">
+  <object id="1" name="chest" x="0" y="0" width="32" height="32"/>
+ </objectgroup>
+ <objectgroup id="5" name="triggers">
+  <object id="2" name="door" x="32" y="0" width="32" height="32"/>
+ </objectgroup>
+</map>`, 1)
+	var last Progress
+	m, err := NewMap(writeTestMap(t, tmx),
+		WithLayerFilter(func(name string) bool {
+			return name == "ground" || name == "objects"
+		}),
+		WithProgress(func(p Progress) { last = p }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var layers []string
+	for _, l := range m.Layers() {
+		layers = append(layers, l.Name())
+	}
+	if fmt.Sprint(layers) != "[ground]" {
+		t.Errorf("layers: %v, expected: [ground]", layers)
+	}
+	var objLayers []string
+	for _, ol := range m.ObjectLayers() {
+		objLayers = append(objLayers, ol.Name())
+	}
+	if fmt.Sprint(objLayers) != "[objects]" {
+		t.Errorf("object layers: %v, expected: [objects]", objLayers)
+	}
+	// Tileset image, tile layer and object layer.
+	if last != (Progress{3, 3}) {
+		t.Errorf("final progress: %v, expected: %v", last, Progress{3, 3})
+	}
+}
@@ -25,8 +25,10 @@
 package stone
 
 import (
+	"context"
 	"fmt"
-	"path/filepath"
+	"image/color"
+	"math"
 
 	"github.com/salviati/go-tmx/tmx"
 
@@ -35,125 +37,88 @@ import (
 
 // Struct for graphical representation of TMX map.
 type Map struct {
-	tmxMap      *tmx.Map
-	tilesets    map[string]pixel.Picture
-	tileBatches map[pixel.Picture]*pixel.Batch
-	tilesize    pixel.Vec
-	mapsize     pixel.Vec
-	tilescount  pixel.Vec
-	layers      []*Layer
-}
-
-// NewMap creates new map from .tmx file with specified path.
-func NewMap(path string) (*Map, error) {
-	tmxMap, err := tmxMap(path)
+	tmxMap         *tmx.Map
+	tilesets       []*Tileset
+	tilesize       pixel.Vec
+	mapsize        pixel.Vec
+	tilescount     pixel.Vec
+	layers         []*Layer
+	objLayers      []*ObjectLayer
+	tileProps      map[tileKey]map[string]string
+	wangSets       []*WangSet
+	fog            *Fog
+	renderer       *PixelRenderer
+	cache          *ResourceCache
+	cached         []*cachedPicture
+	path           string
+	opts           *options
+	order          string
+	background     color.Color
+	parallaxOrigin pixel.Vec
+	costs          *moveCostCache
+}
+
+// NewMap creates new map from .tmx file with specified path
+// and options.
+func NewMap(path string, opts ...Option) (*Map, error) {
+	return NewMapContext(context.Background(), path, opts...)
+}
+
+// Path returns path to the map file.
+func (m *Map) Path() string {
+	return m.path
+}
+
+// Reload loads map again from the map file, with the same
+// options, and replaces all map data with loaded data.
+// Map fog of war is kept and resized to the new map size.
+// Map is not changed if loading fails. Layers, objects and
+// other map elements retrieved before reload are not updated,
+// so they should be retrieved again after reload.
+func (m *Map) Reload() error {
+	nm, err := loadMap(context.Background(), m.path, m.opts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to retive TMX map: %v", err)
+		return err
 	}
-	m := new(Map)
-	m.tmxMap = tmxMap
-	m.tilesize = pixel.V(float64(m.tmxMap.TileWidth),
-		float64(m.tmxMap.TileHeight))
-	m.tilescount = pixel.V(float64(m.tmxMap.Width),
-		float64(m.tmxMap.Height))
-	m.mapsize = pixel.V(float64(int(m.tilesize.X*m.tilescount.X)),
-		float64(int(m.tilesize.Y*m.tilescount.Y)))
-	m.tilesets = make(map[string]pixel.Picture)
-	m.tileBatches = make(map[pixel.Picture]*pixel.Batch)
-	mapDir := filepath.Dir(path)
-	// Tilesets.
-	for _, ts := range m.tmxMap.Tilesets {
-		tsPath := filepath.FromSlash(mapDir + "/" + ts.Image.Source)
-		tsPic, err := picture(tsPath)
-		if err != nil {
-			return nil, fmt.Errorf("unable to retrieve tilset source: %v",
-				ts.Name)
-		}
-		m.tilesets[ts.Name] = tsPic
-		m.tileBatches[tsPic] = pixel.NewBatch(&pixel.TrianglesData{}, tsPic)
+	m.Release()
+	fog := m.fog
+	*m = *nm
+	for _, l := range m.layers {
+		l.areaMap = m
 	}
-	// Map layers.
-	for _, l := range m.tmxMap.Layers {
-		layer, err := newLayer(m, l)
-		if err != nil {
-			return nil, fmt.Errorf("unable to create layer: %s: %v",
-				l.Name, err)
-		}
-		m.layers = append(m.layers, layer)
+	if fog != nil {
+		fog.fit()
+		m.fog = fog
 	}
-	return m, nil
+	return nil
+}
+
+// Release releases all map tileset pictures held in resource
+// cache specified on map creation. Map should not be drawn
+// after release.
+func (m *Map) Release() {
+	if m.cache == nil {
+		return
+	}
+	for _, cp := range m.cached {
+		m.cache.release(cp)
+	}
+	m.cached = nil
 }
 
 // DrawSize use specified matrix and size to draw map on target.
 // Draws part of the map in specified size starting from position
 // specified in given matrix.
 func (m *Map) DrawPart(tar pixel.Target, matrix pixel.Matrix, size pixel.Vec) {
-	drawArea := pixel.R(matrix[4], matrix[5], matrix[4]+size.X,
-		matrix[5]+size.Y)
-	// Clear all tilesets draw batches.
-	for _, batch := range m.tileBatches {
-		batch.Clear()
-	}
-	// Draw layers tiles to tilesets batechs.
-	for _, l := range m.layers {
-		for _, t := range l.tiles {
-			tilePos := pixel.V(t.Position().X*matrix[0], t.Position().Y*matrix[0])
-			if drawArea.Contains(tilePos) {
-				batch := m.tileBatches[t.Picture()]
-				if batch == nil {
-					continue
-				}
-				tileDrawPos := mapDrawPos(t.Position(), matrix)
-				t.Draw(batch, pixel.IM.Scaled(pixel.V(0, 0),
-					matrix[0]).Moved(tileDrawPos))
-			}
-		}
-	}
-	// Draw bateches with layer tiles.
-	drawn := make(map[pixel.Picture]*pixel.Batch)
-	for _, l := range m.layers {
-		for _, t := range l.tiles {
-			batch := m.tileBatches[t.Picture()]
-			if batch == nil || drawn[t.Picture()] != nil {
-				continue
-			}
-			batch.Draw(tar)
-			drawn[t.Picture()] = batch
-		}
-	}
+	m.RenderPart(m.pixelRenderer(tar), matrix, size)
 }
 
 // Draw use specified matrix to draw map on target.
 // Draws whole map starting from position specified in given matrix.
+// Parallax of map layers is relative to the center of the target,
+// if target has bounds, like Pixel window or canvas.
 func (m *Map) Draw(tar pixel.Target, matrix pixel.Matrix) {
-	// Clear all tilesets draw batches.
-	for _, batch := range m.tileBatches {
-		batch.Clear()
-	}
-	// Draw layers tile to tileset batechs.
-	for _, l := range m.layers {
-		for _, t := range l.tiles {
-			batch := m.tileBatches[t.Picture()]
-			if batch == nil {
-				continue
-			}
-			tileDrawPos := mapDrawPos(t.Position(), matrix)
-			t.Draw(batch, pixel.IM.Scaled(pixel.V(0, 0),
-				matrix[0]).Moved(tileDrawPos))
-		}
-	}
-	// Draw bateches with layer tiles.
-	drawn := make(map[pixel.Picture]*pixel.Batch)
-	for _, l := range m.layers {
-		for _, t := range l.tiles {
-			batch := m.tileBatches[t.Picture()]
-			if batch == nil || drawn[t.Picture()] != nil {
-				continue
-			}
-			batch.Draw(tar)
-			drawn[t.Picture()] = batch
-		}
-	}
+	m.Render(m.pixelRenderer(tar), matrix)
 }
 
 // TileSize returns size of singe map tile.
@@ -166,11 +131,124 @@ func (m *Map) Size() pixel.Vec {
 	return m.mapsize
 }
 
+// Orientation returns map orientation from TMX data.
+func (m *Map) Orientation() string {
+	return m.tmxMap.Orientation
+}
+
+// RenderOrder returns map render order from TMX data:
+// right-down, right-up, left-down or left-up.
+// Tiles of each layer are drawn in this order.
+func (m *Map) RenderOrder() string {
+	if len(m.order) < 1 {
+		return "right-down"
+	}
+	return m.order
+}
+
+// BackgroundColor returns map background color from TMX
+// data, or nil if map has no background color.
+func (m *Map) BackgroundColor() color.Color {
+	return m.background
+}
+
+// ParallaxOrigin returns map position of the parallax
+// origin. Layers with any parallax factor are drawn at the
+// same position when the view is centered on the origin.
+func (m *Map) ParallaxOrigin() pixel.Vec {
+	return m.parallaxOrigin
+}
+
+// Tilesets returns all map tilesets.
+func (m *Map) Tilesets() []*Tileset {
+	return m.tilesets
+}
+
+// Tileset returns tileset with specified name, or nil
+// if there is no such tileset in the map.
+func (m *Map) Tileset(name string) *Tileset {
+	for _, ts := range m.tilesets {
+		if ts.name == name {
+			return ts
+		}
+	}
+	return nil
+}
+
+// AddLayer creates new empty layer with specified name
+// on top of all map layers.
+func (m *Map) AddLayer(name string) *Layer {
+	l := emptyLayer(m, name)
+	m.layers = append(m.layers, l)
+	m.resetMoveCost()
+	return l
+}
+
+// ObjectLayers returns all map object layers.
+func (m *Map) ObjectLayers() []*ObjectLayer {
+	return m.objLayers
+}
+
+// WangSets returns Wang sets of all map tilesets.
+func (m *Map) WangSets() []*WangSet {
+	return m.wangSets
+}
+
+// WangSet returns Wang set with specified name, or nil
+// if there is no such Wang set in map tilesets.
+func (m *Map) WangSet(name string) *WangSet {
+	for _, ws := range m.wangSets {
+		if ws.name == name {
+			return ws
+		}
+	}
+	return nil
+}
+
+// TilesCount returns number of tiles in map
+// row(X) and column(Y).
+func (m *Map) TilesCount() pixel.Vec {
+	return m.tilescount
+}
+
+// Cell returns grid coordinates of the map cell on
+// specified position, false is returned if position
+// is outside the map.
+func (m *Map) Cell(pos pixel.Vec) (x, y int, ok bool) {
+	gridPos := m.gridPos(pos)
+	x, y = int(math.Floor(gridPos.X)), int(math.Floor(gridPos.Y))
+	return x, y, m.cellOnMap(x, y)
+}
+
+// CellBounds returns bounds of the map cell with
+// specified grid coordinates.
+func (m *Map) CellBounds(x, y int) pixel.Rect {
+	min := m.mapPos(pixel.V(float64(x), float64(y+1)))
+	max := m.mapPos(pixel.V(float64(x+1), float64(y)))
+	return pixel.R(min.X, min.Y, max.X, max.Y)
+}
+
+// Bounds returns map area covered by map cells.
+func (m *Map) Bounds() pixel.Rect {
+	return pixel.R(0, 0, m.mapsize.X, m.mapsize.Y)
+}
+
 // Layers returns all map layers.
 func (m *Map) Layers() []*Layer {
 	return m.layers
 }
 
+// SetFog sets fog of war to draw over the map,
+// nil removes the fog.
+func (m *Map) SetFog(f *Fog) {
+	m.fog = f
+}
+
+// Fog returns fog of war drawn over the map.
+func (m *Map) Fog() *Fog {
+	return m.fog
+}
+
 // PositionLayer returns visible layer on specified
 // position on map or nil if there is no tiles on
 // this position.
@@ -186,21 +264,83 @@ func (m *Map) PositionLayer(p pixel.Vec) *Layer {
 	return visibleLayer
 }
 
-// tileBounds returns bounds for tile with specified size and ID
-// from specified tileset picture.
-func (m *Map) tileBounds(tileset pixel.Picture, tileID tmx.ID) pixel.Rect {
-	tilesetSize := roundTilesetSize(tileset.Bounds().Max, m.tilesize)
-	tileCount := 0
-	for h := tilesetSize.Y - m.tilesize.Y; h >= 0; h -= m.tilesize.Y {
-		for w := 0.0; w+m.tilesize.X <= tilesetSize.X; w += m.tilesize.X {
-			if tileCount == int(tileID) {
-				tileBounds := pixel.R(w, h, w+m.tilesize.X,
-					h+m.tilesize.Y)
-				return tileBounds
-			}
-			tileCount++
+// cellTile creates new tile with specified ID from tileset with
+// specified name, for map cell with specified grid coordinates.
+func (m *Map) cellTile(tileset string, id tmx.ID, x, y int) (*Tile, error) {
+	ts := m.Tileset(tileset)
+	if ts == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTilesetNotFound, tileset)
+	}
+	tilePic := ts.TilePicture(int(id))
+	if tilePic == nil {
+		return nil, fmt.Errorf("tileset: %s: no image for tile: %d", tileset, id)
+	}
+	// Tiles larger than map cell are anchored to the bottom-left
+	// corner of the cell, as in Tiled.
+	tilePos := m.mapPos(pixel.V(float64(x), float64(y+1)))
+	tile := newTile(tilePic, ts.tileFrame(id), tilePos.Add(ts.offset))
+	tile.drawIndex = m.drawIndex(x, y)
+	tile.properties = m.tileProps[tileKey{tileset, id}]
+	return tile, nil
+}
+
+// gidTileset returns tileset with tile with specified
+// global ID, or nil if there is no such tileset.
+func (m *Map) gidTileset(gid int) *Tileset {
+	gid &= tmx.GIDMask
+	var tileset *Tileset
+	for _, ts := range m.tilesets {
+		if ts.firstGID <= gid && (tileset == nil || ts.firstGID > tileset.firstGID) {
+			tileset = ts
 		}
 	}
-	return pixel.R(0, 0, 0, 0)
+	return tileset
+}
+
+// pixelRenderer returns map renderer for specified
+// Pixel target. Renderer is created on the first call and
+// reused, so renderer batches are kept between frames.
+func (m *Map) pixelRenderer(tar pixel.Target) *PixelRenderer {
+	if m.renderer == nil {
+		m.renderer = NewPixelRenderer(tar)
+		m.renderer.SetSmooth(m.opts.smooth)
+	}
+	m.renderer.SetTarget(tar)
+	return m.renderer
+}
+
+// drawIndex returns index of map cell with specified grid
+// coordinates in map render order.
+func (m *Map) drawIndex(x, y int) int {
+	width, height := int(m.tilescount.X), int(m.tilescount.Y)
+	switch m.order {
+	case "right-up":
+		y = height - 1 - y
+	case "left-down":
+		x = width - 1 - x
+	case "left-up":
+		x, y = width-1-x, height-1-y
+	}
+	return y*width + x
+}
+
+// cellOnMap checks if cell with specified grid coordinates
+// is on the map.
+func (m *Map) cellOnMap(x, y int) bool {
+	return x >= 0 && y >= 0 && x < int(m.tilescount.X) &&
+		y < int(m.tilescount.Y)
+}
+
+// gridPos translates specified map position to grid position.
+// Grid Y axis grows down, as in TMX data, and one grid
+// unit is equal to the size of a single tile.
+func (m *Map) gridPos(pos pixel.Vec) pixel.Vec {
+	return pixel.V(pos.X/m.tilesize.X,
+		(m.mapsize.Y-pos.Y)/m.tilesize.Y)
+}
 
+// mapPos translates specified grid position to map position.
+func (m *Map) mapPos(gridPos pixel.Vec) pixel.Vec {
+	return pixel.V(gridPos.X*m.tilesize.X,
+		m.mapsize.Y-gridPos.Y*m.tilesize.Y)
 }
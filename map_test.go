@@ -0,0 +1,164 @@
+/*
+ * map_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopxl/pixel"
+
+	"github.com/isangeles/stone/internal/testgrid"
+)
+
+// testTileset is TMX tileset for test map grid.
+var testTileset = testgrid.Tileset()
+
+// writeTestImage writes PNG image with specified size, filled
+// with specified color, to file with specified path.
+func writeTestImage(t *testing.T, path string, w, h int, c color.Color) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	err = png.Encode(file, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeTestMap writes specified TMX data to map file in
+// temporary directory with test tileset image. Returns path
+// to the map file.
+func writeTestMap(t *testing.T, tmx string) string {
+	t.Helper()
+	return testgrid.WriteMap(t, tmx)
+}
+
+// gridTMX returns TMX data of map with single layer with
+// cells from specified grid rows.
+func gridTMX(rows ...string) string {
+	return testgrid.TMX(rows...)
+}
+
+// testMap creates map with single layer with cells from
+// specified grid rows.
+func testMap(t *testing.T, rows ...string) *Map {
+	t.Helper()
+	m, err := NewMap(writeTestMap(t, gridTMX(rows...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMapCoordinates(t *testing.T) {
+	m := testMap(t,
+		".#",
+		"..",
+	)
+	if m.Bounds() != pixel.R(0, 0, 64, 64) {
+		t.Errorf("map bounds: %v, expected: %v", m.Bounds(), pixel.R(0, 0, 64, 64))
+	}
+	tile := m.Layers()[0].TileAt(1, 0)
+	if tile.Position() != pixel.V(32, 32) {
+		t.Errorf("tile position: %v, expected: %v", tile.Position(), pixel.V(32, 32))
+	}
+	if tile.Bounds() != m.CellBounds(1, 0) {
+		t.Errorf("tile bounds: %v, expected: %v", tile.Bounds(), m.CellBounds(1, 0))
+	}
+	x, y, ok := m.Cell(pixel.V(40, 60))
+	if !ok || x != 1 || y != 0 {
+		t.Errorf("cell: %d, %d, %v, expected: 1, 0, true", x, y, ok)
+	}
+	if m.PositionLayer(pixel.V(1, 1)) == nil {
+		t.Errorf("no layer in the bottom-left corner of the map")
+	}
+	if m.PositionLayer(pixel.V(63, 63)) == nil {
+		t.Errorf("no layer in the top-right corner of the map")
+	}
+	if m.PositionLayer(pixel.V(65, 30)) != nil {
+		t.Errorf("layer outside the map")
+	}
+}
+
+func TestBackgroundColor(t *testing.T) {
+	tmx := strings.Replace(gridTMX(".."), `tileheight="32"`,
+		`tileheight="32" backgroundcolor="#80336699"`, 1)
+	m, err := NewMap(writeTestMap(t, tmx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := color.NRGBA{0x33, 0x66, 0x99, 0x80}
+	if m.BackgroundColor() != expected {
+		t.Errorf("background color: %v, expected: %v", m.BackgroundColor(),
+			expected)
+	}
+	tmx = strings.Replace(gridTMX(".."), `tileheight="32"`,
+		`tileheight="32" backgroundcolor="#zz3366"`, 1)
+	_, err = NewMap(writeTestMap(t, tmx))
+	var colorErr *InvalidColorError
+	if !errors.As(err, &colorErr) {
+		t.Fatalf("error: %v, expected invalid color error", err)
+	}
+	if colorErr.Attribute != "backgroundcolor" || colorErr.Value != "#zz3366" {
+		t.Errorf("invalid color error: %s, %s, expected: backgroundcolor, #zz3366",
+			colorErr.Attribute, colorErr.Value)
+	}
+}
+
+func TestDrawReusesRenderer(t *testing.T) {
+	m := testMap(t, "..")
+	tile := m.Layers()[0].TileAt(0, 0)
+	first := pixel.NewBatch(&pixel.TrianglesData{}, tile.Picture())
+	second := pixel.NewBatch(&pixel.TrianglesData{}, tile.Picture())
+	m.Draw(first, pixel.IM)
+	renderer := m.renderer
+	m.DrawPart(second, pixel.IM, pixel.V(32, 32))
+	if renderer == nil || m.renderer != renderer {
+		t.Errorf("map renderer not reused between draws")
+	}
+	if m.renderer.Target() != second {
+		t.Errorf("map renderer not retargeted")
+	}
+	tile.Draw(first, pixel.IM)
+	sprite := tile.sprite
+	tile.Draw(second, pixel.IM)
+	if sprite == nil || tile.sprite != sprite {
+		t.Errorf("tile sprite not reused between draws")
+	}
+}
@@ -0,0 +1,269 @@
+/*
+ * object.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/salviati/go-tmx/tmx"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for map object layer.
+type ObjectLayer struct {
+	name       string
+	objects    []*Object
+	properties map[string]string
+}
+
+// Struct for map object.
+type Object struct {
+	name       string
+	objType    string
+	bounds     pixel.Rect
+	polygon    []pixel.Vec
+	properties map[string]string
+}
+
+// newObjectLayer creates new object layer for specified map.
+// Additional group data is used to read object classes.
+func newObjectLayer(m *Map, tmxGroup tmx.ObjectGroup, data tmxObjectGroup) (*ObjectLayer, error) {
+	ol := new(ObjectLayer)
+	ol.name = tmxGroup.Name
+	ol.properties = properties(tmxGroup.Properties)
+	for i, tmxObj := range tmxGroup.Objects {
+		ob, err := newObject(m, tmxObj, data.objectClass(i))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create object: %s: %w",
+				tmxObj.Name, err)
+		}
+		ol.objects = append(ol.objects, ob)
+	}
+	return ol, nil
+}
+
+// newObject creates new object for specified map. Specified
+// class is used as object type if TMX object has no type.
+func newObject(m *Map, tmxObj tmx.Object, class string) (*Object, error) {
+	ob := new(Object)
+	ob.name = tmxObj.Name
+	ob.objType = tmxObj.Type
+	if len(ob.objType) < 1 {
+		ob.objType = class
+	}
+	ob.properties = properties(tmxObj.Properties)
+	x, y := tmxObj.X, tmxObj.Y
+	if tmxObj.GID != 0 {
+		x, y = m.alignTileObject(tmxObj)
+	}
+	if len(tmxObj.Polygons) > 0 {
+		points, err := tmxPoints(tmxObj.Polygons[0].Points)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse polygon: %w", err)
+		}
+		for _, p := range points {
+			ob.polygon = append(ob.polygon, m.tmxPos(pixel.V(x+p.X, y+p.Y)))
+		}
+		ob.bounds = polygonBounds(ob.polygon)
+		return ob, nil
+	}
+	min := m.tmxPos(pixel.V(x, y+tmxObj.Height))
+	max := m.tmxPos(pixel.V(x+tmxObj.Width, y))
+	ob.bounds = pixel.R(min.X, min.Y, max.X, max.Y)
+	return ob, nil
+}
+
+// Name returns object layer name from tmx data.
+func (ol *ObjectLayer) Name() string {
+	return ol.name
+}
+
+// Objects returns all layer objects.
+func (ol *ObjectLayer) Objects() []*Object {
+	return ol.objects
+}
+
+// Properties returns object layer properties from tmx data.
+func (ol *ObjectLayer) Properties() map[string]string {
+	return ol.properties
+}
+
+// Name returns object name from tmx data.
+func (o *Object) Name() string {
+	return o.name
+}
+
+// Type returns object type from tmx data. For maps saved
+// with Tiled 1.9 or newer this is object class.
+func (o *Object) Type() string {
+	return o.objType
+}
+
+// Bounds returns object bounds on the map.
+func (o *Object) Bounds() pixel.Rect {
+	return o.bounds
+}
+
+// Polygon returns map positions of object polygon points,
+// or nil if object is not a polygon.
+func (o *Object) Polygon() []pixel.Vec {
+	return o.polygon
+}
+
+// Properties returns object properties from tmx data.
+func (o *Object) Properties() map[string]string {
+	return o.properties
+}
+
+// Contains checks if specified map position is inside
+// the object.
+func (o *Object) Contains(pos pixel.Vec) bool {
+	if !o.bounds.Contains(pos) {
+		return false
+	}
+	if o.polygon == nil {
+		return true
+	}
+	return polygonContains(o.polygon, pos)
+}
+
+// Intersects checks if specified rectangle on the map
+// intersects with the object.
+func (o *Object) Intersects(r pixel.Rect) bool {
+	if !o.bounds.Intersects(r) {
+		return false
+	}
+	if o.polygon == nil {
+		return true
+	}
+	for _, p := range o.polygon {
+		if r.Contains(p) {
+			return true
+		}
+	}
+	for _, v := range r.Vertices() {
+		if polygonContains(o.polygon, v) {
+			return true
+		}
+	}
+	for i := range o.polygon {
+		edge := pixel.L(o.polygon[i], o.polygon[(i+1)%len(o.polygon)])
+		if len(r.IntersectionPoints(edge)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// alignTileObject returns TMX position of the top-left corner
+// of specified tile object, moved by tileset alignment and
+// tile offset. Tile objects are aligned to the bottom-left
+// corner by default.
+func (m *Map) alignTileObject(tmxObj tmx.Object) (x, y float64) {
+	x, y = tmxObj.X, tmxObj.Y
+	w, h := tmxObj.Width, tmxObj.Height
+	alignment := ""
+	ts := m.gidTileset(tmxObj.GID)
+	if ts != nil {
+		alignment = ts.alignment
+		// Tileset offset Y axis grows up, as on the map.
+		x, y = x+ts.offset.X, y-ts.offset.Y
+	}
+	switch alignment {
+	case "topleft":
+	case "top":
+		x -= w / 2
+	case "topright":
+		x -= w
+	case "left":
+		y -= h / 2
+	case "center":
+		x, y = x-w/2, y-h/2
+	case "right":
+		x, y = x-w, y-h/2
+	case "bottom":
+		x, y = x-w/2, y-h
+	case "bottomright":
+		x, y = x-w, y-h
+	default:
+		y -= h
+	}
+	return x, y
+}
+
+// tmxPos translates specified TMX pixel position to map
+// position.
+func (m *Map) tmxPos(pos pixel.Vec) pixel.Vec {
+	return m.mapPos(pixel.V(pos.X/m.tilesize.X, pos.Y/m.tilesize.Y))
+}
+
+// tmxPoints parses specified TMX points string.
+func tmxPoints(s string) ([]pixel.Vec, error) {
+	points := make([]pixel.Vec, 0)
+	for _, ps := range strings.Fields(s) {
+		coords := strings.Split(ps, ",")
+		if len(coords) != 2 {
+			return nil, fmt.Errorf("invalid point: %s", ps)
+		}
+		x, err := strconv.ParseFloat(coords[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point X: %w", err)
+		}
+		y, err := strconv.ParseFloat(coords[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point Y: %w", err)
+		}
+		points = append(points, pixel.V(x, y))
+	}
+	return points, nil
+}
+
+// polygonBounds returns bounds of polygon with specified points.
+func polygonBounds(points []pixel.Vec) pixel.Rect {
+	if len(points) < 1 {
+		return pixel.ZR
+	}
+	bounds := pixel.R(points[0].X, points[0].Y, points[0].X, points[0].Y)
+	for _, p := range points[1:] {
+		bounds = bounds.Union(pixel.R(p.X, p.Y, p.X, p.Y))
+	}
+	return bounds
+}
+
+// polygonContains checks if specified position is inside
+// polygon with specified points.
+func polygonContains(points []pixel.Vec, pos pixel.Vec) bool {
+	contains := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		a, b := points[i], points[j]
+		if (a.Y > pos.Y) != (b.Y > pos.Y) &&
+			pos.X < (b.X-a.X)*(pos.Y-a.Y)/(b.Y-a.Y)+a.X {
+			contains = !contains
+		}
+	}
+	return contains
+}
@@ -0,0 +1,174 @@
+/*
+ * options.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"image"
+	"io"
+	"io/fs"
+)
+
+// Type for map creation options.
+type Option func(*options)
+
+// Interface for tileset images loader. Loader receives path
+// to the image file and reader with file content, so it can
+// choose decoder by file extension, decrypt file content or
+// process decoded image, e.g. swap palette. For images
+// embedded in the map file loader receives path to the map file.
+type ImageLoader interface {
+	LoadImage(path string, r io.Reader) (image.Image, error)
+}
+
+// Type for function that loads tileset image, implements
+// ImageLoader interface.
+type ImageLoaderFunc func(path string, r io.Reader) (image.Image, error)
+
+// Type for function that decodes tileset image, implements
+// ImageLoader interface.
+type ImageDecoder func(r io.Reader) (image.Image, error)
+
+// Struct for map creation options.
+type options struct {
+	cache         *ResourceCache
+	progress      func(Progress)
+	fsys          fs.FS
+	fsysID        any
+	layerFilter   func(name string) bool
+	smooth        bool
+	background    bool
+	imageLoader   ImageLoader
+	imageLoaderID any
+}
+
+// WithFS sets file system for map file and tileset images.
+// Map path and paths of tileset images are then paths in this
+// file system, as used by io/fs package. By default files are
+// read from OS file system.
+// Resource cache shares pictures only between maps from the same
+// file system. File systems that are not comparable, like
+// fstest.MapFS, are identified by the option, so the same option
+// should be used for all maps that share pictures.
+func WithFS(fsys fs.FS) Option {
+	id := resourceID(fsys)
+	return func(o *options) {
+		o.fsys = fsys
+		o.fsysID = id
+	}
+}
+
+// WithLayerFilter sets function that decides which map layers
+// and object layers should be loaded. Only layers for which
+// function returns true are loaded.
+func WithLayerFilter(f func(name string) bool) Option {
+	return func(o *options) {
+		o.layerFilter = f
+	}
+}
+
+// WithSmooth enables smoothing of scaled and rotated map tiles.
+// Smoothing is applied on Pixel targets that support it,
+// e.g. Pixel window or canvas.
+func WithSmooth(smooth bool) Option {
+	return func(o *options) {
+		o.smooth = smooth
+	}
+}
+
+// WithBackground enables filling of the drawn map area with
+// map background color before drawing map layers. For parts
+// of the map the whole drawn part is filled.
+func WithBackground(background bool) Option {
+	return func(o *options) {
+		o.background = background
+	}
+}
+
+// WithImageDecoder sets decoder for tileset images. By default,
+// or if decoder is nil, images are decoded by image.Decode, so
+// only formats registered in image package can be decoded.
+func WithImageDecoder(d ImageDecoder) Option {
+	if d == nil {
+		return WithImageLoader(nil)
+	}
+	return WithImageLoader(d)
+}
+
+// WithImageLoader sets loader for tileset images. By default,
+// or if loader is nil, images are decoded by image.Decode, so
+// only formats registered in image package can be loaded.
+// Resource cache shares pictures only between maps created with
+// the same loader. Loaders that are not comparable, like
+// functions, are identified by the option, so the same option
+// should be used for all maps that share pictures.
+func WithImageLoader(l ImageLoader) Option {
+	id := resourceID(l)
+	return func(o *options) {
+		o.imageLoader = l
+		o.imageLoaderID = id
+	}
+}
+
+// LoadImage loads image with specified function.
+func (f ImageLoaderFunc) LoadImage(path string, r io.Reader) (image.Image, error) {
+	return f(path, r)
+}
+
+// LoadImage decodes image with specified decoder.
+func (d ImageDecoder) LoadImage(path string, r io.Reader) (image.Image, error) {
+	return d(r)
+}
+
+// WithResourceCache sets resource cache for map tileset
+// pictures, so pictures can be shared between many maps.
+func WithResourceCache(c *ResourceCache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithProgress sets function called with map loading
+// progress after each loaded map element. Function is
+// called on goroutine that creates the map.
+func WithProgress(f func(Progress)) Option {
+	return func(o *options) {
+		o.progress = f
+	}
+}
+
+// loadLayer checks if layer with specified name should
+// be loaded.
+func (o *options) loadLayer(name string) bool {
+	return o.layerFilter == nil || o.layerFilter(name)
+}
+
+// newOptions creates map creation options with
+// specified options applied.
+func newOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,230 @@
+/*
+ * pathfinding.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+// Pathfinding allows searching for paths on stone maps
+// with A* algorithm.
+package pathfinding
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/gopxl/pixel"
+
+	"github.com/isangeles/stone"
+)
+
+// Type for movement directions.
+type Movement int
+
+const (
+	// Movement up, down, left and right.
+	FourDirections Movement = iota
+	// Movement in four directions and diagonally.
+	EightDirections
+)
+
+// Type for diagonal movement rules.
+type CornerCutting int
+
+const (
+	// Diagonal move is not allowed if any of the cells
+	// next to the corner is blocked.
+	NoCornerCutting CornerCutting = iota
+	// Diagonal move is allowed if only one of the cells
+	// next to the corner is blocked.
+	CutOneCorner
+	// Diagonal move is allowed if target cell is not blocked.
+	CutCorners
+)
+
+// Struct for path search configuration.
+type Config struct {
+	Movement      Movement
+	CornerCutting CornerCutting
+}
+
+// Struct for search node.
+type node struct {
+	x, y   int
+	cost   float64
+	score  float64
+	parent *node
+	closed bool
+	index  int
+}
+
+// FindPath searches for the cheapest path between specified map
+// positions. Blocked cells are avoided and cost of entering each
+// cell is taken from the map cell move cost. Returns map positions
+// of centers of all path cells, starting with the cell next to the
+// start position and ending with destination cell.
+func FindPath(m *stone.Map, from, to pixel.Vec, conf Config) ([]pixel.Vec, error) {
+	startX, startY, ok := m.Cell(from)
+	if !ok {
+		return nil, fmt.Errorf("start position outside map: %v", from)
+	}
+	destX, destY, ok := m.Cell(to)
+	if !ok {
+		return nil, fmt.Errorf("destination position outside map: %v", to)
+	}
+	if m.Blocked(destX, destY) {
+		return nil, fmt.Errorf("destination cell blocked: %d, %d", destX, destY)
+	}
+	width := int(m.TilesCount().X)
+	minCost := m.MinMoveCost()
+	nodes := make(map[int]*node)
+	start := &node{x: startX, y: startY}
+	start.score = heuristic(startX, startY, destX, destY, conf) * minCost
+	nodes[startY*width+startX] = start
+	open := &nodeQueue{start}
+	dirs := directions(conf)
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		if current.x == destX && current.y == destY {
+			return waypoints(m, current), nil
+		}
+		current.closed = true
+		for _, d := range dirs {
+			x, y := current.x+d.x, current.y+d.y
+			if m.Blocked(x, y) || !canMove(m, current, d, conf) {
+				continue
+			}
+			cost := current.cost + m.MoveCost(x, y)*d.length
+			n := nodes[y*width+x]
+			if n == nil {
+				n = &node{x: x, y: y, index: -1}
+				nodes[y*width+x] = n
+			} else if n.closed || cost >= n.cost {
+				continue
+			}
+			n.cost = cost
+			n.score = cost + heuristic(x, y, destX, destY, conf)*minCost
+			n.parent = current
+			if n.index < 0 {
+				heap.Push(open, n)
+			} else {
+				heap.Fix(open, n.index)
+			}
+		}
+	}
+	return nil, fmt.Errorf("no path found")
+}
+
+// Struct for move direction.
+type direction struct {
+	x, y   int
+	length float64
+}
+
+var (
+	orthogonalDirections = []direction{
+		{0, -1, 1}, {1, 0, 1}, {0, 1, 1}, {-1, 0, 1},
+	}
+	diagonalDirections = []direction{
+		{1, -1, math.Sqrt2}, {1, 1, math.Sqrt2},
+		{-1, 1, math.Sqrt2}, {-1, -1, math.Sqrt2},
+	}
+)
+
+// directions returns move directions for specified configuration.
+func directions(conf Config) []direction {
+	if conf.Movement == EightDirections {
+		return append(orthogonalDirections, diagonalDirections...)
+	}
+	return orthogonalDirections
+}
+
+// canMove checks if move in specified direction from specified
+// node is allowed by corner cutting rules.
+func canMove(m *stone.Map, n *node, d direction, conf Config) bool {
+	if d.x == 0 || d.y == 0 {
+		return true
+	}
+	blocked := 0
+	if m.Blocked(n.x+d.x, n.y) {
+		blocked++
+	}
+	if m.Blocked(n.x, n.y+d.y) {
+		blocked++
+	}
+	switch conf.CornerCutting {
+	case CutCorners:
+		return true
+	case CutOneCorner:
+		return blocked < 2
+	default:
+		return blocked == 0
+	}
+}
+
+// heuristic returns estimated distance between specified cells.
+func heuristic(x, y, destX, destY int, conf Config) float64 {
+	dx := math.Abs(float64(destX - x))
+	dy := math.Abs(float64(destY - y))
+	if conf.Movement == EightDirections {
+		return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+	}
+	return dx + dy
+}
+
+// waypoints returns map positions of cells centers on path
+// ending with specified node.
+func waypoints(m *stone.Map, end *node) []pixel.Vec {
+	path := make([]pixel.Vec, 0)
+	for n := end; n.parent != nil; n = n.parent {
+		path = append(path, m.CellBounds(n.x, n.y).Center())
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Type for queue of open search nodes, sorted by node score.
+type nodeQueue []*node
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool { return q[i].score < q[j].score }
+
+func (q nodeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *nodeQueue) Push(x any) {
+	n := x.(*node)
+	n.index = len(*q)
+	*q = append(*q, n)
+}
+
+func (q *nodeQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	n.index = -1
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,135 @@
+/*
+ * pathfinding_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package pathfinding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gopxl/pixel"
+
+	"github.com/isangeles/stone"
+	"github.com/isangeles/stone/internal/testgrid"
+)
+
+// testMap creates map with cells from specified grid rows.
+func testMap(t *testing.T, rows ...string) *stone.Map {
+	t.Helper()
+	path := testgrid.WriteMap(t, testgrid.TMX(rows...))
+	m, err := stone.NewMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// cells returns map positions of centers of cells with
+// specified grid coordinates.
+func cells(m *stone.Map, coords ...[2]int) []pixel.Vec {
+	path := make([]pixel.Vec, 0)
+	for _, c := range coords {
+		path = append(path, m.CellBounds(c[0], c[1]).Center())
+	}
+	return path
+}
+
+func TestFindPath(t *testing.T) {
+	eight := Config{Movement: EightDirections}
+	tests := []struct {
+		name     string
+		rows     []string
+		from, to [2]int
+		conf     Config
+		path     [][2]int
+	}{
+		{"straight", []string{"...."}, [2]int{0, 0}, [2]int{3, 0}, Config{},
+			[][2]int{{1, 0}, {2, 0}, {3, 0}}},
+		{"same cell", []string{".."}, [2]int{0, 0}, [2]int{0, 0}, Config{},
+			[][2]int{}},
+		{"around wall", []string{".#.", "..."}, [2]int{0, 0}, [2]int{2, 0}, Config{},
+			[][2]int{{0, 1}, {1, 1}, {2, 1}, {2, 0}}},
+		{"blocked", []string{".#.", ".#."}, [2]int{0, 0}, [2]int{2, 0}, eight, nil},
+		{"destination blocked", []string{"..#"}, [2]int{0, 0}, [2]int{2, 0}, Config{}, nil},
+		{"diagonal", []string{"...", "...", "..."}, [2]int{0, 0}, [2]int{2, 2}, eight,
+			[][2]int{{1, 1}, {2, 2}}},
+		{"no corner cutting", []string{".#", ".."}, [2]int{0, 0}, [2]int{1, 1}, eight,
+			[][2]int{{0, 1}, {1, 1}}},
+		{"cut one corner", []string{".#", ".."}, [2]int{0, 0}, [2]int{1, 1},
+			Config{EightDirections, CutOneCorner}, [][2]int{{1, 1}}},
+		{"cut one corner between walls", []string{".#", "#."}, [2]int{0, 0}, [2]int{1, 1},
+			Config{EightDirections, CutOneCorner}, nil},
+		{"cut corners", []string{".#", "#."}, [2]int{0, 0}, [2]int{1, 1},
+			Config{EightDirections, CutCorners}, [][2]int{{1, 1}}},
+		{"weighted", []string{".s.", "..."}, [2]int{0, 0}, [2]int{2, 0}, Config{},
+			[][2]int{{0, 1}, {1, 1}, {2, 1}, {2, 0}}},
+		{"weighted shortcut", []string{".s.", ".#.", ".#.", "..."}, [2]int{0, 0}, [2]int{2, 0}, Config{},
+			[][2]int{{1, 0}, {2, 0}}},
+		{"negative cost", []string{".n."}, [2]int{0, 0}, [2]int{2, 0}, Config{}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := testMap(t, test.rows...)
+			from := m.CellBounds(test.from[0], test.from[1]).Center()
+			to := m.CellBounds(test.to[0], test.to[1]).Center()
+			path, err := FindPath(m, from, to, test.conf)
+			if test.path == nil {
+				if err == nil {
+					t.Fatalf("expected no path, found: %v", path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to find path: %v", err)
+			}
+			expected := cells(m, test.path...)
+			if fmt.Sprint(path) != fmt.Sprint(expected) {
+				t.Errorf("path: %v, expected: %v", path, expected)
+			}
+		})
+	}
+}
+
+func TestFindPathOutsideMap(t *testing.T) {
+	m := testMap(t, "..")
+	_, err := FindPath(m, pixel.V(-10, 10), pixel.V(40, 10), Config{})
+	if err == nil {
+		t.Errorf("expected error for start outside map")
+	}
+	_, err = FindPath(m, pixel.V(10, 10), pixel.V(100, 10), Config{})
+	if err == nil {
+		t.Errorf("expected error for destination outside map")
+	}
+}
+
+func TestHeuristic(t *testing.T) {
+	four := heuristic(0, 0, 3, 4, Config{})
+	if four != 7 {
+		t.Errorf("four directions heuristic: %f, expected: 7", four)
+	}
+	eight := heuristic(0, 0, 3, 4, Config{Movement: EightDirections})
+	expected := 4 + 3*(1.4142135623730951-1)
+	if eight != expected {
+		t.Errorf("octile heuristic: %f, expected: %f", eight, expected)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * pixelrenderer.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"image/color"
+
+	"github.com/gopxl/pixel"
+	"github.com/gopxl/pixel/imdraw"
+)
+
+// Struct for renderer that draws map on Pixel target.
+// Tiles are collected in batches, one for each tileset
+// picture, and drawn on flush. Batches are drawn before
+// flush if needed to keep tiles in the drawing order.
+type PixelRenderer struct {
+	target  pixel.Target
+	batches map[pixel.Picture]*pixel.Batch
+	drawn   []*pixel.Batch
+	sprite  *pixel.Sprite
+	rects   *imdraw.IMDraw
+	smooth  bool
+}
+
+// Interface for Pixel targets with smoothing, like
+// Pixel window or canvas.
+type smoothTarget interface {
+	Smooth() bool
+	SetSmooth(smooth bool)
+}
+
+// NewPixelRenderer creates new renderer for specified
+// Pixel target.
+func NewPixelRenderer(tar pixel.Target) *PixelRenderer {
+	r := new(PixelRenderer)
+	r.target = tar
+	r.batches = make(map[pixel.Picture]*pixel.Batch)
+	r.sprite = pixel.NewSprite(nil, pixel.ZR)
+	r.rects = imdraw.New(nil)
+	return r
+}
+
+// SetTarget sets Pixel target for renderer.
+func (r *PixelRenderer) SetTarget(tar pixel.Target) {
+	r.target = tar
+}
+
+// SetSmooth enables smoothing of scaled and rotated tiles.
+// Smoothing is enabled on renderer target only for drawing
+// tiles, if target supports smoothing.
+func (r *PixelRenderer) SetSmooth(smooth bool) {
+	r.smooth = smooth
+}
+
+// Smooth checks if smoothing of tiles is enabled.
+func (r *PixelRenderer) Smooth() bool {
+	return r.smooth
+}
+
+// Bounds returns bounds of renderer target, or zero
+// rectangle if target bounds are unknown.
+func (r *PixelRenderer) Bounds() pixel.Rect {
+	if b, ok := r.target.(bounded); ok {
+		return b.Bounds()
+	}
+	return pixel.ZR
+}
+
+// Target returns renderer Pixel target.
+func (r *PixelRenderer) Target() pixel.Target {
+	return r.target
+}
+
+// DrawTile draws tile to batch for tile picture.
+func (r *PixelRenderer) DrawTile(t *Tile, matrix pixel.Matrix) {
+	batch := r.batches[t.Picture()]
+	if batch == nil {
+		batch = pixel.NewBatch(&pixel.TrianglesData{}, t.Picture())
+		r.batches[t.Picture()] = batch
+	}
+	if r.batchDrawn(batch) && r.drawn[len(r.drawn)-1] != batch {
+		// Tile added to batch drawn earlier would be drawn
+		// below tiles from later batches.
+		r.drawBatches()
+	}
+	if !r.batchDrawn(batch) {
+		r.drawn = append(r.drawn, batch)
+	}
+	r.sprite.Set(t.Picture(), t.Frame())
+	r.sprite.Draw(batch, matrix)
+}
+
+// FillRect adds specified rectangle to rectangles drawn
+// on flush.
+func (r *PixelRenderer) FillRect(rect pixel.Rect, c color.Color) {
+	r.rects.Color = c
+	r.rects.Push(rect.Min, rect.Max)
+	r.rects.Rectangle(0)
+}
+
+// Flush draws all batches with tiles and then all
+// rectangles on renderer target.
+func (r *PixelRenderer) Flush() {
+	r.drawBatches()
+	r.rects.Draw(r.target)
+	r.rects.Clear()
+}
+
+// drawBatches draws all batches with tiles on renderer
+// target and clears them.
+func (r *PixelRenderer) drawBatches() {
+	if st, ok := r.target.(smoothTarget); ok && r.smooth && !st.Smooth() {
+		st.SetSmooth(true)
+		defer st.SetSmooth(false)
+	}
+	for _, batch := range r.drawn {
+		batch.Draw(r.target)
+		batch.Clear()
+	}
+	r.drawn = r.drawn[:0]
+}
+
+// batchDrawn checks if tiles were drawn to specified
+// batch since the last flush.
+func (r *PixelRenderer) batchDrawn(batch *pixel.Batch) bool {
+	for _, b := range r.drawn {
+		if b == batch {
+			return true
+		}
+	}
+	return false
+}
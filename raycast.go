@@ -0,0 +1,119 @@
+/*
+ * raycast.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for ray cast hit.
+type RaycastHit struct {
+	// Top blocking tile in hit cell.
+	Tile *Tile
+	// Grid coordinates of hit cell.
+	CellX, CellY int
+	// Map position where ray entered hit cell.
+	Point pixel.Vec
+	// Normal of hit cell side, zero vector if ray
+	// started inside blocked cell.
+	Normal pixel.Vec
+}
+
+// Raycast casts ray between specified map positions and
+// returns hit with the first blocked map cell on its way.
+// False is returned if ray reached target position without
+// hitting any blocked cell.
+func (m *Map) Raycast(from, to pixel.Vec) (RaycastHit, bool) {
+	start := m.gridPos(from)
+	dir := m.gridPos(to).Sub(start)
+	x, y := int(math.Floor(start.X)), int(math.Floor(start.Y))
+	if t := m.rayBlockingTile(x, y); t != nil {
+		return RaycastHit{Tile: t, CellX: x, CellY: y, Point: from}, true
+	}
+	stepX, nextX, deltaX := raySteps(start.X, dir.X)
+	stepY, nextY, deltaY := raySteps(start.Y, dir.Y)
+	for {
+		var dist float64
+		var normal pixel.Vec
+		if nextX < nextY {
+			dist = nextX
+			x += stepX
+			nextX += deltaX
+			normal = pixel.V(float64(-stepX), 0)
+		} else {
+			dist = nextY
+			y += stepY
+			nextY += deltaY
+			// Grid Y axis is inverted.
+			normal = pixel.V(0, float64(stepY))
+		}
+		if dist > 1 {
+			return RaycastHit{}, false
+		}
+		if t := m.rayBlockingTile(x, y); t != nil {
+			hit := RaycastHit{
+				Tile:   t,
+				CellX:  x,
+				CellY:  y,
+				Point:  m.mapPos(start.Add(dir.Scaled(dist))),
+				Normal: normal,
+			}
+			return hit, true
+		}
+	}
+}
+
+// HasLineOfSight checks if there is no blocked map cell
+// between specified map positions.
+func (m *Map) HasLineOfSight(from, to pixel.Vec) bool {
+	_, hit := m.Raycast(from, to)
+	return !hit
+}
+
+// rayBlockingTile returns top blocking tile in map cell with
+// specified grid coordinates. Unlike for movement, cells
+// outside the map are not considered as blocked for rays.
+func (m *Map) rayBlockingTile(x, y int) *Tile {
+	if !m.cellOnMap(x, y) {
+		return nil
+	}
+	return m.blockingTile(x, y)
+}
+
+// raySteps returns cell step, ray distance to the first
+// cell border and ray distance between cell borders for
+// ray starting at specified position and moving with
+// specified delta along one grid axis.
+func raySteps(pos, delta float64) (step int, next, dist float64) {
+	switch {
+	case delta > 0:
+		return 1, (math.Floor(pos) + 1 - pos) / delta, 1 / delta
+	case delta < 0:
+		return -1, (pos - math.Floor(pos)) / -delta, 1 / -delta
+	default:
+		return 0, math.Inf(1), math.Inf(1)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * raycast_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+func TestRaycast(t *testing.T) {
+	m := testMap(t,
+		"...",
+		".#.",
+		"...",
+	)
+	tests := []struct {
+		name     string
+		from, to pixel.Vec
+		hit      bool
+		x, y     int
+		point    pixel.Vec
+		normal   pixel.Vec
+	}{
+		{"from left", pixel.V(16, 48), pixel.V(80, 48), true, 1, 1,
+			pixel.V(32, 48), pixel.V(-1, 0)},
+		{"from right", pixel.V(80, 48), pixel.V(16, 48), true, 1, 1,
+			pixel.V(64, 48), pixel.V(1, 0)},
+		{"from top", pixel.V(48, 80), pixel.V(48, 16), true, 1, 1,
+			pixel.V(48, 64), pixel.V(0, 1)},
+		{"from bottom", pixel.V(48, 16), pixel.V(48, 80), true, 1, 1,
+			pixel.V(48, 32), pixel.V(0, -1)},
+		{"diagonal", pixel.V(16, 16), pixel.V(80, 48), true, 1, 1,
+			pixel.V(48, 32), pixel.V(0, -1)},
+		{"inside wall", pixel.V(40, 40), pixel.V(80, 80), true, 1, 1,
+			pixel.V(40, 40), pixel.ZV},
+		{"short of wall", pixel.V(16, 48), pixel.V(30, 48), false, 0, 0,
+			pixel.ZV, pixel.ZV},
+		{"along edge", pixel.V(16, 16), pixel.V(80, 16), false, 0, 0,
+			pixel.ZV, pixel.ZV},
+		{"outside map", pixel.V(-16, 112), pixel.V(112, 112), false, 0, 0,
+			pixel.ZV, pixel.ZV},
+		{"from equal to", pixel.V(16, 16), pixel.V(16, 16), false, 0, 0,
+			pixel.ZV, pixel.ZV},
+		{"from equal to in wall", pixel.V(48, 48), pixel.V(48, 48), true, 1, 1,
+			pixel.V(48, 48), pixel.ZV},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hit, ok := m.Raycast(test.from, test.to)
+			if ok != test.hit {
+				t.Fatalf("hit: %v, expected: %v", ok, test.hit)
+			}
+			if m.HasLineOfSight(test.from, test.to) == test.hit {
+				t.Errorf("line of sight: %v, expected: %v", test.hit, !test.hit)
+			}
+			if !ok {
+				return
+			}
+			if hit.Tile == nil {
+				t.Errorf("no hit tile")
+			}
+			if hit.CellX != test.x || hit.CellY != test.y {
+				t.Errorf("hit cell: %d, %d, expected: %d, %d", hit.CellX,
+					hit.CellY, test.x, test.y)
+			}
+			if hit.Point != test.point {
+				t.Errorf("hit point: %v, expected: %v", hit.Point, test.point)
+			}
+			if hit.Normal != test.normal {
+				t.Errorf("hit normal: %v, expected: %v", hit.Normal, test.normal)
+			}
+		})
+	}
+}
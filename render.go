@@ -0,0 +1,154 @@
+/*
+ * render.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/gopxl/pixel"
+)
+
+// Interface for map renderers. Renderer draws map tiles and
+// overlays on its target, so the map itself holds only map
+// data and is not tied to any graphic library or context.
+// Target positions have Y axis growing up.
+type Renderer interface {
+	// DrawTile draws tile frame transformed by specified
+	// matrix. Matrix translates frame positions, relative
+	// to the frame center, to target positions.
+	DrawTile(t *Tile, matrix pixel.Matrix)
+	// FillRect fills specified target area with specified
+	// color.
+	FillRect(r pixel.Rect, c color.Color)
+	// Flush finishes drawing of all tiles and rectangles
+	// since the last flush, tiles are drawn first.
+	Flush()
+}
+
+// Interface for renderers and render targets with
+// known bounds.
+type bounded interface {
+	Bounds() pixel.Rect
+}
+
+// Render use specified matrix to draw map with specified
+// renderer. Draws whole map starting from position specified
+// in given matrix.
+func (m *Map) Render(r Renderer, matrix pixel.Matrix) {
+	m.render(r, matrix, nil, m.layers)
+}
+
+// RenderPart use specified matrix and size to draw map with
+// specified renderer. Draws part of the map in specified size
+// starting from position specified in given matrix.
+func (m *Map) RenderPart(r Renderer, matrix pixel.Matrix, size pixel.Vec) {
+	drawArea := pixel.R(0, 0, size.X, size.Y)
+	m.render(r, matrix, &drawArea, m.layers)
+}
+
+// render use specified matrix to draw specified map layers with
+// renderer. If draw area is specified then only tiles inside this
+// area of the renderer target are drawn. Layers are moved by
+// parallax offsets for map position in the center of the view.
+func (m *Map) render(r Renderer, matrix pixel.Matrix, area *pixel.Rect,
+	layers []*Layer) {
+	if m.opts.background && m.background != nil {
+		bgRect := drawRect(m.Bounds(), matrix)
+		if area != nil {
+			bgRect = *area
+		}
+		r.FillRect(bgRect, m.background)
+		r.Flush()
+	}
+	view := m.view(r, area)
+	viewCenter := mapViewPos(view.Center(), matrix)
+	for _, l := range layers {
+		layerMatrix := l.drawMatrix(matrix, viewCenter)
+		if l.image != nil {
+			m.renderImage(r, l, layerMatrix, view, area)
+		}
+		for _, t := range l.tiles {
+			if area != nil && !area.Intersects(drawRect(t.bounds, layerMatrix)) {
+				continue
+			}
+			r.DrawTile(t, t.drawMatrix(layerMatrix))
+		}
+		r.Flush()
+	}
+	if m.fog != nil {
+		m.fog.render(r, matrix, area)
+		r.Flush()
+	}
+}
+
+// renderImage use specified layer matrix to draw image of
+// specified image layer with renderer. Repeated image is drawn
+// many times to fill specified view of the renderer target.
+// If draw area is specified then only images inside this area
+// are drawn.
+func (m *Map) renderImage(r Renderer, l *Layer, matrix pixel.Matrix, view pixel.Rect,
+	area *pixel.Rect) {
+	bounds := l.image.bounds
+	size := bounds.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+	// Map area visible in the view.
+	min := mapViewPos(view.Min, matrix)
+	max := mapViewPos(view.Max, matrix)
+	fromX, toX, fromY, toY := 0, 0, 0, 0
+	if l.repeatX && view.W() > 0 {
+		fromX = int(math.Floor((min.X - bounds.Min.X) / size.X))
+		toX = int(math.Ceil((max.X-bounds.Min.X)/size.X)) - 1
+	}
+	if l.repeatY && view.H() > 0 {
+		fromY = int(math.Floor((min.Y - bounds.Min.Y) / size.Y))
+		toY = int(math.Ceil((max.Y-bounds.Min.Y)/size.Y)) - 1
+	}
+	imageMatrix := l.image.drawMatrix(matrix)
+	for y := fromY; y <= toY; y++ {
+		for x := fromX; x <= toX; x++ {
+			offset := pixel.V(float64(x)*size.X, float64(y)*size.Y)
+			if area != nil && !area.Intersects(drawRect(bounds.Moved(offset), matrix)) {
+				continue
+			}
+			// The same tile is drawn in each place, moved by
+			// the repeat offset.
+			r.DrawTile(l.image, imageMatrix.Moved(offset.Scaled(matrix[0])))
+		}
+	}
+}
+
+// view returns specified draw area, or bounds of renderer
+// target if there is no draw area specified.
+func (m *Map) view(r Renderer, area *pixel.Rect) pixel.Rect {
+	if area != nil {
+		return *area
+	}
+	if b, ok := r.(bounded); ok {
+		return b.Bounds()
+	}
+	return pixel.ZR
+}
@@ -0,0 +1,176 @@
+/*
+ * render_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+var update = flag.Bool("update", false, "update golden images")
+
+func TestDrawImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		area  func(m *Map) pixel.Rect
+		scale float64
+	}{
+		{"example", "example/map/res/map.tmx", (*Map).Bounds, 0.25},
+		{"flips", "testdata/render/flips.tmx", (*Map).Bounds, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewMap(test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name, m.DrawImage(test.area(m), test.scale))
+		})
+	}
+}
+
+func TestRenderOrder(t *testing.T) {
+	orders := []string{"right-down", "right-up", "left-down", "left-up"}
+	for _, order := range orders {
+		t.Run(order, func(t *testing.T) {
+			path := filepath.Join("testdata", "render", "order-"+order+".tmx")
+			m, err := NewMap(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, "order-"+order, m.DrawImage(m.DrawBounds(), 1))
+		})
+	}
+}
+
+func TestRenderBackground(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"background", []Option{WithBackground(true)}},
+		{"no-background", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join("testdata", "render", "background.tmx")
+			m, err := NewMap(path, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, test.name, m.DrawImage(m.Bounds(), 1))
+		})
+	}
+}
+
+func TestRenderParallax(t *testing.T) {
+	m, err := NewMap(filepath.Join("testdata", "render", "parallax.tmx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Area with center outside the map center, so the parallax
+	// layer is moved relative to the tile layer.
+	checkGolden(t, "parallax", m.DrawImage(pixel.R(32, 0, 192, 64), 1))
+}
+
+func TestRenderRepeat(t *testing.T) {
+	m, err := NewMap(filepath.Join("testdata", "render", "repeat.tmx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "repeat", m.DrawImage(m.Bounds(), 1))
+	// View below and left of the map origin, with negative
+	// repeat indices.
+	img := image.NewRGBA(image.Rect(0, 0, 96, 64))
+	m.RenderPart(NewImageRenderer(img), pixel.IM.Moved(pixel.V(-100, -60)),
+		pixel.V(96, 64))
+	checkGolden(t, "repeat-part", img)
+}
+
+// checkGolden compares specified image with golden image with
+// specified name, golden image is updated first if requested.
+func checkGolden(t *testing.T, name string, img *image.RGBA) {
+	t.Helper()
+	golden := filepath.Join("testdata", "render", "golden", name+".png")
+	if *update {
+		writeGolden(t, golden, img)
+	}
+	compareGolden(t, golden, img)
+}
+
+// writeGolden writes specified image to golden image file
+// with specified path.
+func writeGolden(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	err = png.Encode(file, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// compareGolden compares specified image with golden image
+// from file with specified path.
+func compareGolden(t *testing.T, path string, img *image.RGBA) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unable to open golden image: %v", err)
+	}
+	defer file.Close()
+	golden, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("unable to decode golden image: %v", err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("image bounds: %v, expected: %v", img.Bounds(),
+			golden.Bounds())
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			gr, gg, gb, ga := golden.At(x, y).RGBA()
+			if r != gr || g != gg || b != gb || a != ga {
+				t.Fatalf("pixel %d, %d: %v, expected: %v", x, y,
+					img.At(x, y), golden.At(x, y))
+			}
+		}
+	}
+}
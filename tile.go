@@ -29,20 +29,37 @@ import (
 
 // Struct for map tile.
 type Tile struct {
-	*pixel.Sprite
-	bounds pixel.Rect
+	picture    pixel.Picture
+	sprite     *pixel.Sprite
+	frame      pixel.Rect
+	bounds     pixel.Rect
+	drawIndex  int
+	properties map[string]string
+	hFlip      bool
+	vFlip      bool
+	dFlip      bool
 }
 
-// newTile creates new map tile with specified sprite
-// and position.
-func newTile(spr *pixel.Sprite, pos pixel.Vec) *Tile {
+// newTile creates new map tile with specified frame of
+// tileset picture and position of the bottom-left corner.
+func newTile(pic pixel.Picture, frame pixel.Rect, pos pixel.Vec) *Tile {
 	t := new(Tile)
-	t.Sprite = spr
-	t.bounds = pixel.R(pos.X, pos.Y, pos.X + t.Sprite.Frame().W(),
-		pos.Y + t.Sprite.Frame().H())
+	t.picture = pic
+	t.frame = frame
+	t.bounds = pixel.R(pos.X, pos.Y, pos.X+frame.W(), pos.Y+frame.H())
 	return t
 }
 
+// Picture returns tileset picture of the tile.
+func (t *Tile) Picture() pixel.Picture {
+	return t.picture
+}
+
+// Frame returns tile frame in tileset picture.
+func (t *Tile) Frame() pixel.Rect {
+	return t.frame
+}
+
 // Position returns tile position.
 func (t *Tile) Position() pixel.Vec {
 	return t.bounds.Min
@@ -52,3 +69,59 @@ func (t *Tile) Position() pixel.Vec {
 func (t *Tile) Bounds() pixel.Rect {
 	return t.bounds
 }
+
+// Properties returns tile properties from tileset data.
+func (t *Tile) Properties() map[string]string {
+	return t.properties
+}
+
+// Draw draws tile on specified Pixel target, with tile flips.
+// Tile is drawn with the center on position specified by
+// given matrix. Use Map.Render to draw many tiles.
+func (t *Tile) Draw(tar pixel.Target, matrix pixel.Matrix) {
+	if t.sprite == nil {
+		t.sprite = pixel.NewSprite(t.picture, t.frame)
+	}
+	t.sprite.Draw(tar, t.flipMatrix().Chained(matrix))
+}
+
+// drawMatrix returns matrix for drawing tile frame with
+// specified map draw matrix.
+func (t *Tile) drawMatrix(matrix pixel.Matrix) pixel.Matrix {
+	return t.flipMatrix().Scaled(pixel.ZV, matrix[0]).Moved(mapDrawPos(t.drawPos(), matrix))
+}
+
+// drawPos returns map position of the drawn tile center.
+// Tile is drawn over the tile bounds.
+func (t *Tile) drawPos() pixel.Vec {
+	return t.bounds.Center()
+}
+
+// setFlips sets horizontal, vertical and diagonal flip
+// of the tile. Diagonally flipped tile has swapped width
+// and height, with the same bottom-left corner.
+func (t *Tile) setFlips(h, v, d bool) {
+	t.hFlip, t.vFlip = h, v
+	if d != t.dFlip {
+		min := t.bounds.Min
+		t.bounds = pixel.R(min.X, min.Y, min.X+t.bounds.H(), min.Y+t.bounds.W())
+	}
+	t.dFlip = d
+}
+
+// flipMatrix returns matrix with tile flips, applied in
+// the same order as in Tiled: diagonal flip first, then
+// horizontal and vertical flip.
+func (t *Tile) flipMatrix() pixel.Matrix {
+	matrix := pixel.IM
+	if t.dFlip {
+		matrix = matrix.Chained(pixel.Matrix{0, -1, -1, 0, 0, 0})
+	}
+	if t.hFlip {
+		matrix = matrix.ScaledXY(pixel.ZV, pixel.V(-1, 1))
+	}
+	if t.vFlip {
+		matrix = matrix.ScaledXY(pixel.ZV, pixel.V(1, -1))
+	}
+	return matrix
+}
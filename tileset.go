@@ -0,0 +1,233 @@
+/*
+ * tileset.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"github.com/salviati/go-tmx/tmx"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for map tileset.
+type Tileset struct {
+	name      string
+	firstGID  int
+	tilesize  pixel.Vec
+	tilecount int
+	margin    float64
+	spacing   float64
+	picture   pixel.Picture
+	path      string
+	tiles     map[tmx.ID]*tilesetTile
+	offset    pixel.Vec
+	alignment string
+}
+
+// Struct for tile of image collection tileset.
+type tilesetTile struct {
+	picture pixel.Picture
+	path    string
+}
+
+// newTileset creates new tileset with specified picture
+// loaded from file with specified path, from TMX tileset data.
+func newTileset(tmxTileset tmx.Tileset, pic pixel.Picture, path string) *Tileset {
+	ts := new(Tileset)
+	ts.path = path
+	ts.name = tmxTileset.Name
+	ts.firstGID = int(tmxTileset.FirstGID)
+	ts.tilesize = pixel.V(float64(tmxTileset.TileWidth),
+		float64(tmxTileset.TileHeight))
+	ts.tilecount = tmxTileset.Tilecount
+	ts.margin = float64(tmxTileset.Margin)
+	ts.spacing = float64(tmxTileset.Spacing)
+	ts.picture = pic
+	if ts.tilecount < 1 {
+		columns, rows := ts.grid()
+		ts.tilecount = columns * rows
+	}
+	return ts
+}
+
+// newCollectionTileset creates new image collection tileset,
+// without tile images, from TMX tileset data.
+func newCollectionTileset(tmxTileset tmx.Tileset) *Tileset {
+	ts := new(Tileset)
+	ts.name = tmxTileset.Name
+	ts.firstGID = int(tmxTileset.FirstGID)
+	ts.tilesize = pixel.V(float64(tmxTileset.TileWidth),
+		float64(tmxTileset.TileHeight))
+	ts.tilecount = tmxTileset.Tilecount
+	ts.tiles = make(map[tmx.ID]*tilesetTile)
+	return ts
+}
+
+// Name returns tileset name.
+func (ts *Tileset) Name() string {
+	return ts.name
+}
+
+// FirstGID returns global ID of the first tileset tile.
+func (ts *Tileset) FirstGID() int {
+	return ts.firstGID
+}
+
+// TileSize returns size of single tileset tile.
+func (ts *Tileset) TileSize() pixel.Vec {
+	return ts.tilesize
+}
+
+// TileCount returns number of tiles in tileset.
+func (ts *Tileset) TileCount() int {
+	return ts.tilecount
+}
+
+// ImagePath returns path to tileset image file, or empty
+// string if the image is embedded in the map file or tileset
+// is an image collection.
+func (ts *Tileset) ImagePath() string {
+	return ts.path
+}
+
+// Picture returns tileset picture, or nil if tileset is
+// an image collection.
+func (ts *Tileset) Picture() pixel.Picture {
+	return ts.picture
+}
+
+// TileOffset returns offset applied to positions of all
+// tileset tiles on the map.
+func (ts *Tileset) TileOffset() pixel.Vec {
+	return ts.offset
+}
+
+// ObjectAlignment returns alignment of tile objects with
+// tiles from tileset, relative to object position.
+// Empty alignment is the same as bottom-left alignment.
+func (ts *Tileset) ObjectAlignment() string {
+	return ts.alignment
+}
+
+// Collection checks if tileset is a collection of images,
+// with separate image for each tile.
+func (ts *Tileset) Collection() bool {
+	return ts.tiles != nil
+}
+
+// TilePicture returns picture of tile with specified ID,
+// or nil if there is no such tile in image collection
+// tileset. For tilesets with single image the tileset
+// picture is returned.
+func (ts *Tileset) TilePicture(id int) pixel.Picture {
+	if !ts.Collection() {
+		return ts.picture
+	}
+	t := ts.tiles[tmx.ID(id)]
+	if t == nil {
+		return nil
+	}
+	return t.picture
+}
+
+// TileImagePath returns path to image file of tile with
+// specified ID. For tilesets with single image the path
+// to tileset image file is returned.
+func (ts *Tileset) TileImagePath(id int) string {
+	if !ts.Collection() {
+		return ts.path
+	}
+	t := ts.tiles[tmx.ID(id)]
+	if t == nil {
+		return ""
+	}
+	return t.path
+}
+
+// setTMXData sets tile offset and object alignment
+// from specified TMX tileset data.
+func (ts *Tileset) setTMXData(data tmxTileset) {
+	// Offset Y axis grows down in TMX data.
+	ts.offset = pixel.V(float64(data.TileOffset.X), float64(-data.TileOffset.Y))
+	ts.alignment = data.ObjectAlignment
+}
+
+// addTile adds tile with specified ID, picture and path
+// to image file to image collection tileset.
+func (ts *Tileset) addTile(id tmx.ID, pic pixel.Picture, path string) {
+	ts.tiles[id] = &tilesetTile{pic, path}
+	if len(ts.tiles) > ts.tilecount {
+		ts.tilecount = len(ts.tiles)
+	}
+}
+
+// tileFrame returns frame of tile with specified ID in
+// the tile picture. Tiles in tileset image are placed in
+// rows from the top-left corner of the image, as in Tiled.
+func (ts *Tileset) tileFrame(id tmx.ID) pixel.Rect {
+	if ts.Collection() {
+		return ts.tiles[id].picture.Bounds()
+	}
+	columns, rows := ts.grid()
+	if int(id) >= columns*rows {
+		return pixel.ZR
+	}
+	bounds := ts.picture.Bounds()
+	column, row := float64(int(id)%columns), float64(int(id)/columns)
+	x := bounds.Min.X + ts.margin + column*(ts.tilesize.X+ts.spacing)
+	// Picture Y axis grows up.
+	y := bounds.Max.Y - ts.margin - row*(ts.tilesize.Y+ts.spacing) - ts.tilesize.Y
+	return pixel.R(x, y, x+ts.tilesize.X, y+ts.tilesize.Y)
+}
+
+// grid returns number of tile columns and rows in tileset
+// image.
+func (ts *Tileset) grid() (columns, rows int) {
+	if ts.tilesize.X <= 0 || ts.tilesize.Y <= 0 {
+		return 0, 0
+	}
+	size := ts.picture.Bounds().Size()
+	columns = int((size.X - 2*ts.margin + ts.spacing) / (ts.tilesize.X + ts.spacing))
+	rows = int((size.Y - 2*ts.margin + ts.spacing) / (ts.tilesize.Y + ts.spacing))
+	if columns < 1 || rows < 1 {
+		return 0, 0
+	}
+	return columns, rows
+}
+
+// imagePaths returns paths to all tileset image files.
+func (ts *Tileset) imagePaths() []string {
+	if !ts.Collection() {
+		if len(ts.path) < 1 {
+			return nil
+		}
+		return []string{ts.path}
+	}
+	var paths []string
+	for _, t := range ts.tiles {
+		if len(t.path) > 0 {
+			paths = append(paths, t.path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,173 @@
+/*
+ * tileset_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+// collectionTMX is TMX data of map with image collection
+// tileset, with tiles in other order than IDs, tile image
+// from file and tile image embedded in map file.
+const collectionTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="2" height="1" tilewidth="32" tileheight="32">
+ <tileset firstgid="1" name="objects" tilewidth="64" tileheight="96" tilecount="3" columns="0">
+  <grid orientation="orthogonal" width="1" height="1"/>
+  <tile id="7">
+   <properties><property name="tree" type="bool" value="true"/></properties>
+  </tile>
+  <tile id="5">
+   <image width="64" height="96" source="tree.png"/>
+  </tile>
+  <tile id="2">
+   <image width="32" height="48">
+    <data encoding="base64">%s</data>
+   </image>
+  </tile>
+ </tileset>
+ <layer id="1" name="objects" width="2" height="1">
+  <data encoding="csv">6,3</data>
+ </layer>
+</map>`
+
+func TestCollectionTileset(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 48))
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmx := fmt.Sprintf(collectionTMX, base64.StdEncoding.EncodeToString(buf.Bytes()))
+	path := writeTestMap(t, tmx)
+	writeTestImage(t, filepath.Join(filepath.Dir(path), "tree.png"), 64, 96,
+		color.White)
+	cache := NewResourceCache()
+	m, err := NewMap(path, WithResourceCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		x      int
+		bounds pixel.Rect
+	}{
+		{0, pixel.R(0, 0, 64, 96)},
+		{1, pixel.R(32, 0, 64, 48)},
+	}
+	for _, test := range tests {
+		tile := m.Layers()[0].TileAt(test.x, 0)
+		if tile == nil {
+			t.Errorf("cell %d, 0: no tile", test.x)
+			continue
+		}
+		if tile.Bounds() != test.bounds {
+			t.Errorf("cell %d, 0: bounds: %v, expected: %v", test.x,
+				tile.Bounds(), test.bounds)
+		}
+	}
+	// Embedded images are not cached.
+	if cache.Len() != 1 {
+		t.Errorf("cache pictures: %d, expected: 1", cache.Len())
+	}
+	issues, err := Validate(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) > 0 {
+		t.Errorf("validation issues: %v", issues)
+	}
+}
+
+func TestTileOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset string
+		bounds pixel.Rect
+	}{
+		{"no offset", "", pixel.R(0, 0, 32, 32)},
+		{"positive offset", `<tileoffset x="4" y="8"/>`, pixel.R(4, -8, 36, 24)},
+		{"negative offset", `<tileoffset x="-4" y="-8"/>`, pixel.R(-4, 8, 28, 40)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmx := strings.Replace(gridTMX("..", ".#"), `<image source="tiles.png"`,
+				test.offset+`<image source="tiles.png"`, 1)
+			m, err := NewMap(writeTestMap(t, tmx))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tile := m.Layers()[0].TileAt(0, 1)
+			if tile.Bounds() != test.bounds {
+				t.Errorf("tile bounds: %v, expected: %v", tile.Bounds(),
+					test.bounds)
+			}
+		})
+	}
+}
+
+func TestObjectAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment string
+		bounds    pixel.Rect
+	}{
+		{"default", "", pixel.R(32, 0, 64, 32)},
+		{"bottomleft", "bottomleft", pixel.R(32, 0, 64, 32)},
+		{"center", "center", pixel.R(16, -16, 48, 16)},
+		{"topright", "topright", pixel.R(0, -32, 32, 0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmx := gridTMX("..", ".#")
+			if len(test.alignment) > 0 {
+				tmx = strings.Replace(tmx, `name="tiles"`,
+					`name="tiles" objectalignment="`+test.alignment+`"`, 1)
+			}
+			// Tile object placed by the bottom-left corner of
+			// the bottom-right map cell.
+			tmx = strings.Replace(tmx, "</map>", `<objectgroup id="2" name="objects">
+  <object id="1" gid="1" x="32" y="64" width="32" height="32"/>
+ </objectgroup>
+</map>`, 1)
+			m, err := NewMap(writeTestMap(t, tmx))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ob := m.ObjectLayers()[0].Objects()[0]
+			if ob.Bounds() != test.bounds {
+				t.Errorf("object bounds: %v, expected: %v", ob.Bounds(),
+					test.bounds)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+/*
+ * tmxdata.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/salviati/go-tmx/tmx"
+
+	"github.com/gopxl/pixel"
+)
+
+// Struct for TMX data not parsed by the tmx package.
+type tmxData struct {
+	Infinite     int              `xml:"infinite,attr"`
+	RenderOrder  string           `xml:"renderorder,attr"`
+	Background   string           `xml:"backgroundcolor,attr"`
+	ParallaxX    float64          `xml:"parallaxoriginx,attr"`
+	ParallaxY    float64          `xml:"parallaxoriginy,attr"`
+	Tilesets     []tmxTileset     `xml:"tileset"`
+	Layers       []tmxLayer       `xml:"layer"`
+	ImageLayers  []tmxImageLayer  `xml:"imagelayer"`
+	ObjectGroups []tmxObjectGroup `xml:"objectgroup"`
+	// Names of layer elements in document order.
+	layerOrder []string
+}
+
+// Struct for TMX map child elements, in document order.
+type tmxElements struct {
+	Elements []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+// Struct for additional TMX tileset data.
+type tmxTileset struct {
+	Name            string        `xml:"name,attr"`
+	Source          string        `xml:"source,attr"`
+	ObjectAlignment string        `xml:"objectalignment,attr"`
+	TileOffset      tmxTileOffset `xml:"tileoffset"`
+	Image           tmxImage      `xml:"image"`
+	Tiles           []tmxTile     `xml:"tile"`
+	WangSets        []tmxWangSet  `xml:"wangsets>wangset"`
+}
+
+// Struct for TMX tileset tile offset.
+type tmxTileOffset struct {
+	X int `xml:"x,attr"`
+	Y int `xml:"y,attr"`
+}
+
+// Struct for additional TMX image data.
+type tmxImage struct {
+	Source string        `xml:"source,attr"`
+	Width  int           `xml:"width,attr"`
+	Height int           `xml:"height,attr"`
+	Data   *tmxImageData `xml:"data"`
+}
+
+// Struct for TMX image data embedded in map file.
+type tmxImageData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Content     string `xml:",chardata"`
+}
+
+// Struct for additional TMX tileset tile data.
+type tmxTile struct {
+	ID         tmx.ID         `xml:"id,attr"`
+	Image      tmxImage       `xml:"image"`
+	Properties []tmx.Property `xml:"properties>property"`
+}
+
+// tileImages returns images embedded in TMX data of tileset
+// tiles, by tile ID.
+func (ts *tmxTileset) tileImages() map[tmx.ID]*tmxImageData {
+	images := make(map[tmx.ID]*tmxImageData)
+	for _, t := range ts.Tiles {
+		if t.Image.Data != nil {
+			images[t.ID] = t.Image.Data
+		}
+	}
+	return images
+}
+
+// Struct for TMX tileset Wang set.
+type tmxWangSet struct {
+	Name   string         `xml:"name,attr"`
+	Type   string         `xml:"type,attr"`
+	Colors []tmxWangColor `xml:"wangcolor"`
+	Tiles  []tmxWangTile  `xml:"wangtile"`
+}
+
+// Struct for TMX Wang set color.
+type tmxWangColor struct {
+	Name string `xml:"name,attr"`
+}
+
+// Struct for TMX Wang set tile.
+type tmxWangTile struct {
+	TileID tmx.ID `xml:"tileid,attr"`
+	WangID string `xml:"wangid,attr"`
+}
+
+// Struct for additional TMX layer data.
+type tmxLayer struct {
+	Name      string       `xml:"name,attr"`
+	ParallaxX *float64     `xml:"parallaxx,attr"`
+	ParallaxY *float64     `xml:"parallaxy,attr"`
+	Data      tmxLayerData `xml:"data"`
+}
+
+// Struct for TMX image layer.
+type tmxImageLayer struct {
+	Name       string         `xml:"name,attr"`
+	OffsetX    float64        `xml:"offsetx,attr"`
+	OffsetY    float64        `xml:"offsety,attr"`
+	ParallaxX  *float64       `xml:"parallaxx,attr"`
+	ParallaxY  *float64       `xml:"parallaxy,attr"`
+	RepeatX    int            `xml:"repeatx,attr"`
+	RepeatY    int            `xml:"repeaty,attr"`
+	Properties []tmx.Property `xml:"properties>property"`
+	Image      tmxImage       `xml:"image"`
+}
+
+// Struct for additional TMX object group data.
+type tmxObjectGroup struct {
+	Objects []tmxObject `xml:"object"`
+}
+
+// Struct for additional TMX object data.
+type tmxObject struct {
+	Class string `xml:"class,attr"`
+}
+
+// objectClass returns class of object with specified index,
+// or empty string if object has no class.
+func (og *tmxObjectGroup) objectClass(i int) string {
+	if i < 0 || i >= len(og.Objects) {
+		return ""
+	}
+	return og.Objects[i].Class
+}
+
+// Struct for TMX layer data attributes.
+type tmxLayerData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+}
+
+// parallax returns layer parallax factor.
+func (l *tmxLayer) parallax() pixel.Vec {
+	return tmxParallax(l.ParallaxX, l.ParallaxY)
+}
+
+// parallax returns image layer parallax factor.
+func (l *tmxImageLayer) parallax() pixel.Vec {
+	return tmxParallax(l.ParallaxX, l.ParallaxY)
+}
+
+// tmxParallax returns parallax factor with specified TMX
+// values, factor is 1 for each axis without specified value.
+func tmxParallax(x, y *float64) pixel.Vec {
+	parallax := pixel.V(1, 1)
+	if x != nil {
+		parallax.X = *x
+	}
+	if y != nil {
+		parallax.Y = *y
+	}
+	return parallax
+}
+
+// readLayerOrder reads names of layer elements from specified
+// TMX data, in document order.
+func (d *tmxData) readLayerOrder(tmxBytes []byte) error {
+	elements := new(tmxElements)
+	err := xml.Unmarshal(tmxBytes, elements)
+	if err != nil {
+		return err
+	}
+	for _, e := range elements.Elements {
+		switch e.XMLName.Local {
+		case "layer", "imagelayer", "objectgroup":
+			d.layerOrder = append(d.layerOrder, e.XMLName.Local)
+		}
+	}
+	return nil
+}
+
+// tileKey is key for tileset tile data.
+type tileKey struct {
+	tileset string
+	id      tmx.ID
+}
+
+// unsupportedFeature returns error for the first TMX feature
+// not supported by stone, or nil if all features in data
+// are supported.
+func (d *tmxData) unsupportedFeature() error {
+	if d.Infinite != 0 {
+		return &UnsupportedFeatureError{"infinite map"}
+	}
+	for _, ts := range d.Tilesets {
+		if len(ts.Source) > 0 {
+			return &UnsupportedFeatureError{"external tileset: " + ts.Source}
+		}
+	}
+	for _, l := range d.Layers {
+		switch l.Data.Encoding {
+		case "", "csv", "base64":
+		default:
+			return &UnsupportedFeatureError{"layer encoding: " + l.Data.Encoding}
+		}
+		switch l.Data.Compression {
+		case "", "gzip", "zlib":
+		default:
+			return &UnsupportedFeatureError{"layer compression: " + l.Data.Compression}
+		}
+	}
+	return nil
+}
+
+// decode decodes embedded image data.
+func (d *tmxImageData) decode() ([]byte, error) {
+	if d.Encoding != "base64" {
+		return nil, &UnsupportedFeatureError{"image data encoding: " + d.Encoding}
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d.Content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode base64 data: %w", err)
+	}
+	var r io.Reader
+	switch d.Compression {
+	case "":
+		return data, nil
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case "zlib":
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return nil, &UnsupportedFeatureError{"image data compression: " + d.Compression}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress data: %w", err)
+	}
+	data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress data: %w", err)
+	}
+	return data, nil
+}
+
+// properties creates map with specified TMX properties.
+func properties(tmxProps []tmx.Property) map[string]string {
+	props := make(map[string]string)
+	for _, p := range tmxProps {
+		props[p.Name] = p.Value
+	}
+	return props
+}
@@ -0,0 +1,159 @@
+/*
+ * trigger.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"github.com/gopxl/pixel"
+)
+
+const (
+	// Type of objects used as triggers.
+	TriggerType = "trigger"
+	// Name of object property that marks object as trigger.
+	TriggerProperty = "trigger"
+)
+
+// Struct for set of map trigger regions.
+type Triggers struct {
+	triggers []*Trigger
+}
+
+// Struct for map trigger region.
+type Trigger struct {
+	object  *Object
+	inside  map[string]bool
+	onEnter func(id string)
+	onExit  func(id string)
+	onStay  func(id string)
+}
+
+// NewTriggers creates trigger regions for all map objects
+// with trigger type, or with trigger property set to true.
+func NewTriggers(m *Map) *Triggers {
+	ts := new(Triggers)
+	for _, ol := range m.objLayers {
+		for _, ob := range ol.objects {
+			if ob.objType != TriggerType &&
+				!boolProp(ob.properties, TriggerProperty) {
+				continue
+			}
+			t := &Trigger{object: ob, inside: make(map[string]bool)}
+			ts.triggers = append(ts.triggers, t)
+		}
+	}
+	return ts
+}
+
+// Triggers returns all trigger regions.
+func (ts *Triggers) Triggers() []*Trigger {
+	return ts.triggers
+}
+
+// Trigger returns trigger region for object with
+// specified name, or nil if there is no such trigger.
+func (ts *Triggers) Trigger(name string) *Trigger {
+	for _, t := range ts.triggers {
+		if t.object.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// UpdatePoint updates position of tracked point with specified
+// ID and fires callbacks of all triggers that the point entered,
+// exited or stayed in.
+func (ts *Triggers) UpdatePoint(id string, pos pixel.Vec) {
+	for _, t := range ts.triggers {
+		t.update(id, t.object.Contains(pos))
+	}
+}
+
+// UpdateRect updates area of tracked rectangle with specified
+// ID and fires callbacks of all triggers that the rectangle
+// entered, exited or stayed in.
+func (ts *Triggers) UpdateRect(id string, rect pixel.Rect) {
+	for _, t := range ts.triggers {
+		t.update(id, t.object.Intersects(rect))
+	}
+}
+
+// Remove stops tracking of point or rectangle with specified ID
+// and fires exit callbacks of all triggers that it was inside.
+func (ts *Triggers) Remove(id string) {
+	for _, t := range ts.triggers {
+		t.update(id, false)
+	}
+}
+
+// Object returns trigger map object.
+func (t *Trigger) Object() *Object {
+	return t.object
+}
+
+// Inside checks if tracked point or rectangle with specified
+// ID is inside the trigger.
+func (t *Trigger) Inside(id string) bool {
+	return t.inside[id]
+}
+
+// SetOnEnter sets function triggered when tracked point
+// or rectangle enters the trigger.
+func (t *Trigger) SetOnEnter(f func(id string)) {
+	t.onEnter = f
+}
+
+// SetOnExit sets function triggered when tracked point
+// or rectangle exits the trigger.
+func (t *Trigger) SetOnExit(f func(id string)) {
+	t.onExit = f
+}
+
+// SetOnStay sets function triggered on each update of
+// tracked point or rectangle that stays inside the trigger.
+func (t *Trigger) SetOnStay(f func(id string)) {
+	t.onStay = f
+}
+
+// update updates state of tracked point or rectangle with
+// specified ID and fires suitable callback.
+func (t *Trigger) update(id string, inside bool) {
+	wasInside := t.inside[id]
+	switch {
+	case inside && wasInside:
+		if t.onStay != nil {
+			t.onStay(id)
+		}
+	case inside:
+		t.inside[id] = true
+		if t.onEnter != nil {
+			t.onEnter(id)
+		}
+	case wasInside:
+		delete(t.inside, id)
+		if t.onExit != nil {
+			t.onExit(id)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * trigger_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"testing"
+
+	"github.com/gopxl/pixel"
+)
+
+// triggerTMX is TMX data of map with trigger objects marked
+// with type, class and property, and with objects that are
+// not triggers.
+var triggerTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="4" height="4" tilewidth="32" tileheight="32">
+ ` + testTileset + `
+ <objectgroup id="1" name="objects">
+  <object id="1" name="typed" type="trigger" x="0" y="0" width="32" height="32"/>
+  <object id="2" name="classed" class="trigger" x="32" y="0" width="32" height="32"/>
+  <object id="3" name="property" x="64" y="0" width="32" height="32">
+   <properties>
+    <property name="trigger" type="bool" value="true"/>
+   </properties>
+  </object>
+  <object id="4" name="door" class="door" x="96" y="0" width="32" height="32"/>
+  <object id="5" name="chest" type="chest" class="trigger" x="0" y="96" width="32" height="32"/>
+ </objectgroup>
+</map>`
+
+func TestNewTriggers(t *testing.T) {
+	m, err := NewMap(writeTestMap(t, triggerTMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := NewTriggers(m)
+	var names []string
+	for _, tr := range ts.Triggers() {
+		names = append(names, tr.Object().Name())
+	}
+	expected := []string{"typed", "classed", "property"}
+	if len(names) != len(expected) {
+		t.Fatalf("triggers: %v, expected: %v", names, expected)
+	}
+	for i := range names {
+		if names[i] != expected[i] {
+			t.Errorf("trigger %d: %s, expected: %s", i, names[i], expected[i])
+		}
+	}
+}
+
+func TestObjectType(t *testing.T) {
+	m, err := NewMap(writeTestMap(t, triggerTMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := map[string]string{
+		"typed":    "trigger",
+		"classed":  "trigger",
+		"property": "",
+		"door":     "door",
+		"chest":    "chest",
+	}
+	for _, ob := range m.ObjectLayers()[0].Objects() {
+		if ob.Type() != types[ob.Name()] {
+			t.Errorf("object: %s: type: %s, expected: %s", ob.Name(),
+				ob.Type(), types[ob.Name()])
+		}
+	}
+}
+
+func TestTriggersUpdate(t *testing.T) {
+	m, err := NewMap(writeTestMap(t, triggerTMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := NewTriggers(m)
+	tr := ts.Trigger("classed")
+	if tr == nil {
+		t.Fatal("no trigger")
+	}
+	var events []string
+	tr.SetOnEnter(func(id string) { events = append(events, "enter "+id) })
+	tr.SetOnStay(func(id string) { events = append(events, "stay "+id) })
+	tr.SetOnExit(func(id string) { events = append(events, "exit "+id) })
+	ts.UpdatePoint("player", pixel.V(48, 112))
+	ts.UpdatePoint("player", pixel.V(50, 110))
+	ts.UpdatePoint("player", pixel.V(0, 0))
+	ts.UpdateRect("npc", pixel.R(20, 100, 40, 120))
+	ts.Remove("npc")
+	expected := []string{"enter player", "stay player", "exit player",
+		"enter npc", "exit npc"}
+	if len(events) != len(expected) {
+		t.Fatalf("events: %v, expected: %v", events, expected)
+	}
+	for i := range events {
+		if events[i] != expected[i] {
+			t.Errorf("event %d: %s, expected: %s", i, events[i], expected[i])
+		}
+	}
+}
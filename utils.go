@@ -24,44 +24,123 @@
 package stone
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"image"
+	"image/color"
 	_ "image/png"
-	"math"
+	"io"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
 	
 	"github.com/salviati/go-tmx/tmx"
 
 	"github.com/gopxl/pixel"
 )
 
-// tmxMap retieves tiled map from file with specified path.
-func tmxMap(path string) (*tmx.Map, error) {
-	tmxFile, err := os.Open(path)
+// tmxMap retieves tiled map from file with specified path,
+// from specified file system or from OS file system if there
+// is no file system specified.
+// Also returns additional TMX data not parsed by the tmx package.
+func tmxMap(fsys fs.FS, path string) (*tmx.Map, *tmxData, error) {
+	tmxBytes, err := readFile(fsys, path)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open TMX file: %v", err)
+		return nil, nil, fmt.Errorf("unable to open TMX file: %w", err)
 	}
-	tmxMap, err := tmx.Read(tmxFile)
+	data := new(tmxData)
+	err = xml.Unmarshal(tmxBytes, data)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read TMX file: %v", err)
+		return nil, nil, fmt.Errorf("unable to read TMX data: %w", err)
 	}
-	return tmxMap, nil
+	err = data.readLayerOrder(tmxBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read TMX data: %w", err)
+	}
+	err = data.unsupportedFeature()
+	if err != nil {
+		return nil, nil, err
+	}
+	tmxMap, err := tmx.Read(bytes.NewReader(tmxBytes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read TMX file: %w", err)
+	}
+	return tmxMap, data, nil
 }
 
-// picture retieves picture from file with specified path.
-func picture(path string) (pixel.Picture, error) {
-	file, err := os.Open(path)
+// readFile reads file with specified path from specified
+// file system, or from OS file system if there is no file
+// system specified.
+func readFile(fsys fs.FS, path string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile(path)
+	}
+	return fs.ReadFile(fsys, path)
+}
+
+// openFile opens file with specified path from specified
+// file system, or from OS file system if there is no file
+// system specified.
+func openFile(fsys fs.FS, path string) (fs.File, error) {
+	if fsys == nil {
+		return os.Open(path)
+	}
+	return fsys.Open(path)
+}
+
+// picture retieves picture from file with specified path,
+// from specified file system. Image is loaded with specified
+// loader or decoded with image.Decode if loader is nil.
+func picture(fsys fs.FS, path string, loader ImageLoader) (pixel.Picture, error) {
+	file, err := openFile(fsys, path)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open file: %v", err)
+		return nil, fmt.Errorf("unable to open file: %w", err)
 	}
 	defer file.Close()
-	img, _, err := image.Decode(file)
+	return readPicture(path, file, loader)
+}
+
+// readPicture reads picture from specified reader with image
+// data from specified path. Image is loaded with specified
+// loader or decoded with image.Decode if loader is nil.
+func readPicture(path string, r io.Reader, loader ImageLoader) (pixel.Picture, error) {
+	if loader == nil {
+		loader = ImageDecoder(decodeImage)
+	}
+	img, err := loader.LoadImage(path, r)
 	if err != nil {
-		return nil, fmt.Errorf("unable to decode image: %v", err)
+		return nil, fmt.Errorf("unable to decode image: %w", err)
 	}
 	return pixel.PictureDataFromImage(img), nil
 }
 
+// decodeImage decodes image in any registered format
+// from specified reader.
+func decodeImage(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+// parseColor parses TMX color in #AARRGGBB or #RRGGBB
+// format.
+func parseColor(s string) (color.Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 && len(s) != 8 {
+		return nil, fmt.Errorf("invalid color format: %s", s)
+	}
+	val, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, err
+	}
+	c := color.NRGBA{uint8(val >> 16), uint8(val >> 8), uint8(val), 0xFF}
+	if len(s) == 8 {
+		c.A = uint8(val >> 24)
+	}
+	return c, nil
+}
+
 // mapDrawPos translates real position to map draw position.
 func mapDrawPos(pos pixel.Vec, drawMatrix pixel.Matrix) pixel.Vec {
 	drawPos := pixel.V(drawMatrix[4], drawMatrix[5])
@@ -73,13 +152,15 @@ func mapDrawPos(pos pixel.Vec, drawMatrix pixel.Matrix) pixel.Vec {
 	return pixel.V(posX-drawX, posY-drawY)
 }
 
-// roundTilesetSize rounds tileset size to to value that can be divided
-// by tile size without remainder.
-func roundTilesetSize(tilesetSize pixel.Vec, tileSize pixel.Vec) pixel.Vec {
-	size := pixel.V(0, 0)
-	xCount := math.Floor(tilesetSize.X / tileSize.X)
-	yCount := math.Floor(tilesetSize.Y / tileSize.Y)
-	size.X = tileSize.X * xCount
-	size.Y = tileSize.Y * yCount
-	return size
+// mapViewPos translates draw position to real position.
+func mapViewPos(pos pixel.Vec, matrix pixel.Matrix) pixel.Vec {
+	return pixel.V((pos.X+matrix[4])/matrix[0], (pos.Y+matrix[5])/matrix[0])
+}
+
+// drawRect translates specified map rectangle to draw
+// rectangle.
+func drawRect(rect pixel.Rect, drawMatrix pixel.Matrix) pixel.Rect {
+	min := mapDrawPos(rect.Min, drawMatrix)
+	max := mapDrawPos(rect.Max, drawMatrix)
+	return pixel.R(min.X, min.Y, max.X, max.Y)
 }
@@ -0,0 +1,403 @@
+/*
+ * validate.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+
+	"github.com/salviati/go-tmx/tmx"
+
+	"github.com/gopxl/pixel"
+)
+
+// Type for kinds of map validation issues.
+type IssueKind int
+
+const (
+	// Tileset or image layer image is missing or can't
+	// be decoded.
+	MissingImage IssueKind = iota
+	// Tile GID is outside of any tileset.
+	UnknownGID
+	// Tileset or image layer image size does not match
+	// declared size, or tileset image size does not match
+	// tile grid.
+	TilesetSizeMismatch
+	// Map orientation is not supported.
+	UnsupportedOrientation
+	// Layer data encoding or compression is not supported.
+	UnsupportedEncoding
+	// Other TMX feature is not supported.
+	UnsupportedFeature
+	// Many layers have the same name.
+	DuplicateLayer
+	// Layer required by schema is missing.
+	MissingLayer
+	// Property required by schema is missing.
+	MissingProperty
+	// Color attribute is not a valid TMX color.
+	InvalidColor
+)
+
+// Struct for map validation issue.
+type Issue struct {
+	Kind    IssueKind
+	Message string
+}
+
+// Struct for map validation schema.
+type Schema struct {
+	// Names of required map layers.
+	Layers []string `json:"layers"`
+	// Names of required map properties.
+	MapProperties []string `json:"map-properties"`
+	// Names of required properties of all layers.
+	LayerProperties []string `json:"layer-properties"`
+	// Names of required object properties, by object
+	// type, or class for maps saved with Tiled 1.9 or newer.
+	// Properties for empty type are required for all objects.
+	ObjectProperties map[string][]string `json:"object-properties"`
+}
+
+// String returns issue message.
+func (i Issue) String() string {
+	return i.Message
+}
+
+// Validate checks TMX map from file with specified path for
+// issues that would cause map creation to fail or map to be
+// drawn incorrectly. If schema is specified then map is also
+// checked against this schema. Map file and images are read
+// with file system and image loader from specified options,
+// the same way as on map creation, other options are ignored.
+// Returns an error if TMX file could not be read at all.
+func Validate(path string, schema *Schema, opts ...Option) ([]Issue, error) {
+	o := newOptions(opts)
+	tmxBytes, err := readFile(o.fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open TMX file: %v", err)
+	}
+	tmxMap := new(tmx.Map)
+	err = xml.Unmarshal(tmxBytes, tmxMap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read TMX file: %v", err)
+	}
+	data := new(tmxData)
+	err = xml.Unmarshal(tmxBytes, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read TMX file: %v", err)
+	}
+	v := new(validator)
+	v.path = path
+	v.opts = o
+	v.collections = make(map[string]map[tmx.ID]bool)
+	if tmxMap.Orientation != "orthogonal" {
+		v.add(UnsupportedOrientation, "unsupported orientation: %s",
+			tmxMap.Orientation)
+	}
+	if data.Infinite != 0 {
+		v.add(UnsupportedFeature, "infinite maps are not supported")
+	}
+	if len(data.Background) > 0 {
+		if _, err := parseColor(data.Background); err != nil {
+			v.add(InvalidColor, "invalid backgroundcolor: %s: %v",
+				data.Background, err)
+		}
+	}
+	v.validateTilesets(tmxMap, data)
+	v.validateImageLayers(data)
+	v.validateLayers(tmxMap)
+	if v.encodingsValid {
+		decodedMap, err := tmx.Read(bytes.NewReader(tmxBytes))
+		if err != nil {
+			v.add(UnknownGID, "unable to decode layers: %v", err)
+		} else {
+			v.validateGIDs(decodedMap)
+		}
+	}
+	if schema != nil {
+		v.validateSchema(tmxMap, data, schema)
+	}
+	return v.issues, nil
+}
+
+// Struct for map validator.
+type validator struct {
+	path           string
+	opts           *options
+	issues         []Issue
+	encodingsValid bool
+	collections    map[string]map[tmx.ID]bool
+}
+
+// add adds new issue with specified kind and formatted message.
+func (v *validator) add(kind IssueKind, format string, args ...any) {
+	v.issues = append(v.issues, Issue{kind, fmt.Sprintf(format, args...)})
+}
+
+// validateTilesets checks tilesets of specified map with
+// images embedded in TMX data or placed in files relative
+// to the map file.
+func (v *validator) validateTilesets(tmxMap *tmx.Map, data *tmxData) {
+	for i, ts := range tmxMap.Tilesets {
+		if len(ts.Source) > 0 {
+			v.add(UnsupportedFeature, "tileset: %s: external tilesets are not supported",
+				ts.Source)
+			continue
+		}
+		var tsData tmxTileset
+		if i < len(data.Tilesets) {
+			tsData = data.Tilesets[i]
+		}
+		embedded := tsData.Image.Data
+		if len(ts.Image.Source) < 1 && embedded == nil {
+			if !v.validateCollection(ts, tsData) {
+				v.add(MissingImage, "tileset: %s: no image source", ts.Name)
+			}
+			continue
+		}
+		conf, err := v.imageConfig(embedded, ts.Image.Source)
+		if err != nil {
+			v.add(MissingImage, "tileset: %s: unable to load image: %v",
+				ts.Name, err)
+			continue
+		}
+		if ts.Image.Width > 0 && ts.Image.Height > 0 &&
+			(conf.Width != ts.Image.Width || conf.Height != ts.Image.Height) {
+			v.add(TilesetSizeMismatch, "tileset: %s: image size %dx%d differs from declared %dx%d",
+				ts.Name, conf.Width, conf.Height, ts.Image.Width, ts.Image.Height)
+		}
+		gridWidth := conf.Width - 2*ts.Margin + ts.Spacing
+		gridHeight := conf.Height - 2*ts.Margin + ts.Spacing
+		if ts.TileWidth > 0 && ts.TileHeight > 0 &&
+			(gridWidth%(ts.TileWidth+ts.Spacing) != 0 || gridHeight%(ts.TileHeight+ts.Spacing) != 0) {
+			v.add(TilesetSizeMismatch, "tileset: %s: image size %dx%d is not divisible by tile size %dx%d",
+				ts.Name, conf.Width, conf.Height, ts.TileWidth, ts.TileHeight)
+		}
+	}
+}
+
+// validateCollection checks tile images of specified image
+// collection tileset with images embedded in specified TMX
+// tileset data or placed in files relative to the map file.
+// Returns false if tileset has no tile images.
+func (v *validator) validateCollection(ts tmx.Tileset, data tmxTileset) bool {
+	ids := make(map[tmx.ID]bool)
+	images := data.tileImages()
+	for _, t := range ts.Tiles {
+		embedded := images[t.ID]
+		if len(t.Image.Source) < 1 && embedded == nil {
+			continue
+		}
+		ids[t.ID] = true
+		conf, err := v.imageConfig(embedded, t.Image.Source)
+		if err != nil {
+			v.add(MissingImage, "tileset: %s: tile %d: unable to load image: %v",
+				ts.Name, t.ID, err)
+			continue
+		}
+		if t.Image.Width > 0 && t.Image.Height > 0 &&
+			(conf.Width != t.Image.Width || conf.Height != t.Image.Height) {
+			v.add(TilesetSizeMismatch, "tileset: %s: tile %d: image size %dx%d differs from declared %dx%d",
+				ts.Name, t.ID, conf.Width, conf.Height, t.Image.Width, t.Image.Height)
+		}
+	}
+	if len(ids) < 1 {
+		return false
+	}
+	v.collections[ts.Name] = ids
+	return true
+}
+
+// imageConfig loads specified embedded image, or image from
+// file with specified source path if there is no embedded
+// image, with validator file system and image loader, and
+// returns configuration with the image size.
+func (v *validator) imageConfig(embedded *tmxImageData, source string) (image.Config, error) {
+	var pic pixel.Picture
+	var err error
+	if embedded != nil {
+		var img []byte
+		img, err = embedded.decode()
+		if err != nil {
+			return image.Config{}, err
+		}
+		pic, err = readPicture(v.path, bytes.NewReader(img), v.opts.imageLoader)
+	} else {
+		pic, err = picture(v.opts.fsys, resourcePath(v.opts.fsys, v.path, source),
+			v.opts.imageLoader)
+	}
+	if err != nil {
+		return image.Config{}, err
+	}
+	size := pic.Bounds().Size()
+	return image.Config{Width: int(size.X), Height: int(size.Y)}, nil
+}
+
+// validateImageLayers checks images of image layers from
+// specified TMX data, embedded in TMX data or placed in files
+// relative to the map file. Image layers without image are
+// valid.
+func (v *validator) validateImageLayers(data *tmxData) {
+	for _, il := range data.ImageLayers {
+		img := il.Image
+		if len(img.Source) < 1 && img.Data == nil {
+			continue
+		}
+		conf, err := v.imageConfig(img.Data, img.Source)
+		if err != nil {
+			v.add(MissingImage, "image layer: %s: unable to load image: %v",
+				il.Name, err)
+			continue
+		}
+		if img.Width > 0 && img.Height > 0 &&
+			(conf.Width != img.Width || conf.Height != img.Height) {
+			v.add(TilesetSizeMismatch, "image layer: %s: image size %dx%d differs from declared %dx%d",
+				il.Name, conf.Width, conf.Height, img.Width, img.Height)
+		}
+	}
+}
+
+// validateLayers checks layers encoding and names of
+// specified map.
+func (v *validator) validateLayers(tmxMap *tmx.Map) {
+	v.encodingsValid = true
+	names := make(map[string]bool)
+	for _, l := range tmxMap.Layers {
+		if names[l.Name] {
+			v.add(DuplicateLayer, "layer: %s: duplicate layer name", l.Name)
+		}
+		names[l.Name] = true
+		switch l.Data.Encoding {
+		case "", "csv", "base64":
+		default:
+			v.add(UnsupportedEncoding, "layer: %s: unsupported encoding: %s",
+				l.Name, l.Data.Encoding)
+			v.encodingsValid = false
+		}
+		switch l.Data.Compression {
+		case "", "gzip", "zlib":
+		default:
+			v.add(UnsupportedEncoding, "layer: %s: unsupported compression: %s",
+				l.Name, l.Data.Compression)
+			v.encodingsValid = false
+		}
+	}
+	for _, og := range tmxMap.ObjectGroups {
+		if names[og.Name] {
+			v.add(DuplicateLayer, "layer: %s: duplicate layer name", og.Name)
+		}
+		names[og.Name] = true
+	}
+}
+
+// validateGIDs checks if all tiles of specified decoded map
+// are inside their tilesets.
+func (v *validator) validateGIDs(tmxMap *tmx.Map) {
+	for _, l := range tmxMap.Layers {
+		for i, dt := range l.DecodedTiles {
+			if dt.IsNil() || dt.Tileset == nil {
+				continue
+			}
+			if ids, ok := v.collections[dt.Tileset.Name]; ok {
+				if !ids[dt.ID] {
+					v.add(UnknownGID, "layer: %s: tile %d, %d: no image for tile: %d",
+						l.Name, i%tmxMap.Width, i/tmxMap.Width, dt.ID)
+				}
+				continue
+			}
+			count := tilesetTileCount(dt.Tileset)
+			if count > 0 && int(dt.ID) >= count {
+				v.add(UnknownGID, "layer: %s: tile %d, %d: GID outside any tileset: %d",
+					l.Name, i%tmxMap.Width, i/tmxMap.Width, int(dt.Tileset.FirstGID)+int(dt.ID))
+			}
+		}
+	}
+}
+
+// validateSchema checks if specified map, with specified
+// additional TMX data, has all layers and properties required
+// by specified schema.
+func (v *validator) validateSchema(tmxMap *tmx.Map, data *tmxData, schema *Schema) {
+	mapProps := properties(tmxMap.Properties)
+	for _, p := range schema.MapProperties {
+		if _, ok := mapProps[p]; !ok {
+			v.add(MissingProperty, "map: missing property: %s", p)
+		}
+	}
+	layers := make(map[string]bool)
+	for _, l := range tmxMap.Layers {
+		layers[l.Name] = true
+		props := properties(l.Properties)
+		for _, p := range schema.LayerProperties {
+			if _, ok := props[p]; !ok {
+				v.add(MissingProperty, "layer: %s: missing property: %s", l.Name, p)
+			}
+		}
+	}
+	for i, og := range tmxMap.ObjectGroups {
+		layers[og.Name] = true
+		var groupData tmxObjectGroup
+		if i < len(data.ObjectGroups) {
+			groupData = data.ObjectGroups[i]
+		}
+		for j, ob := range og.Objects {
+			props := properties(ob.Properties)
+			required := append([]string{}, schema.ObjectProperties[""]...)
+			obType := ob.Type
+			if len(obType) < 1 {
+				obType = groupData.objectClass(j)
+			}
+			if len(obType) > 0 {
+				required = append(required, schema.ObjectProperties[obType]...)
+			}
+			for _, p := range required {
+				if _, ok := props[p]; !ok {
+					v.add(MissingProperty, "layer: %s: object: %s: missing property: %s",
+						og.Name, ob.Name, p)
+				}
+			}
+		}
+	}
+	for _, l := range schema.Layers {
+		if !layers[l] {
+			v.add(MissingLayer, "missing layer: %s", l)
+		}
+	}
+}
+
+// tilesetTileCount returns number of tiles in specified
+// tileset, or 0 if number is unknown.
+func tilesetTileCount(ts *tmx.Tileset) int {
+	if ts.Tilecount > 0 {
+		return ts.Tilecount
+	}
+	if ts.TileWidth < 1 || ts.TileHeight < 1 {
+		return 0
+	}
+	return (ts.Image.Width / ts.TileWidth) * (ts.Image.Height / ts.TileHeight)
+}
@@ -0,0 +1,166 @@
+/*
+ * validate_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"testing"
+)
+
+// validateLayer is TMX data of valid map layer.
+const validateLayer = `<layer id="1" name="ground" width="2" height="1">
+  <data encoding="csv">1,2</data>
+ </layer>`
+
+// validateTMX returns TMX data of map with specified
+// orientation, additional map attributes and content.
+func validateTMX(orientation, attrs, content string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="%s" renderorder="right-down" width="2" height="1" tilewidth="32" tileheight="32" %s>
+ %s
+</map>`, orientation, attrs, content)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		tmx    string
+		schema *Schema
+		kinds  []IssueKind
+	}{
+		{"valid", validateTMX("orthogonal", "", testTileset+validateLayer),
+			nil, nil},
+		{"missing image", validateTMX("orthogonal", "",
+			`<tileset firstgid="1" name="tiles" tilewidth="32" tileheight="32" tilecount="4" columns="4">
+  <image source="missing.png" width="128" height="32"/>
+ </tileset>`+validateLayer), nil, []IssueKind{MissingImage}},
+		{"valid image layer", validateTMX("orthogonal", "", testTileset+
+			validateLayer+`<imagelayer id="2" name="sky">
+  <image source="tiles.png" width="128" height="32"/>
+ </imagelayer>`), nil, nil},
+		{"missing layer image", validateTMX("orthogonal", "", testTileset+
+			validateLayer+`<imagelayer id="2" name="sky">
+  <image source="missing.png" width="128" height="32"/>
+ </imagelayer>`), nil, []IssueKind{MissingImage}},
+		{"invalid embedded layer image", validateTMX("orthogonal", "",
+			testTileset+validateLayer+`<imagelayer id="2" name="sky">
+  <image width="128" height="32"><data encoding="base64">AAAA</data></image>
+ </imagelayer>`), nil, []IssueKind{MissingImage}},
+		{"layer image size mismatch", validateTMX("orthogonal", "",
+			testTileset+validateLayer+`<imagelayer id="2" name="sky">
+  <image source="tiles.png" width="64" height="32"/>
+ </imagelayer>`), nil, []IssueKind{TilesetSizeMismatch}},
+		{"unknown GID", validateTMX("orthogonal", "", testTileset+
+			`<layer id="1" name="ground" width="2" height="1">
+  <data encoding="csv">1,9</data>
+ </layer>`), nil, []IssueKind{UnknownGID}},
+		{"tileset size mismatch", validateTMX("orthogonal", "",
+			`<tileset firstgid="1" name="tiles" tilewidth="48" tileheight="32" tilecount="2" columns="2">
+  <image source="tiles.png" width="96" height="32"/>
+ </tileset>`+validateLayer), nil,
+			[]IssueKind{TilesetSizeMismatch, TilesetSizeMismatch}},
+		{"unsupported orientation", validateTMX("isometric", "",
+			testTileset+validateLayer), nil,
+			[]IssueKind{UnsupportedOrientation}},
+		{"unsupported encoding", validateTMX("orthogonal", "", testTileset+
+			`<layer id="1" name="ground" width="2" height="1">
+  <data encoding="base64" compression="lzma">AAAA</data>
+ </layer>`), nil, []IssueKind{UnsupportedEncoding}},
+		{"unsupported feature", validateTMX("orthogonal", `infinite="1"`,
+			testTileset+validateLayer), nil,
+			[]IssueKind{UnsupportedFeature}},
+		{"invalid color", validateTMX("orthogonal", `backgroundcolor="#zz3366"`,
+			testTileset+validateLayer), nil,
+			[]IssueKind{InvalidColor}},
+		{"duplicate layer", validateTMX("orthogonal", "",
+			testTileset+validateLayer+validateLayer), nil,
+			[]IssueKind{DuplicateLayer}},
+		{"missing layer", validateTMX("orthogonal", "",
+			testTileset+validateLayer), &Schema{Layers: []string{"objects"}},
+			[]IssueKind{MissingLayer}},
+		{"missing property", validateTMX("orthogonal", "",
+			testTileset+validateLayer+`<objectgroup id="2" name="objects">
+  <object id="1" name="door" type="door" x="0" y="0"/>
+ </objectgroup>`), &Schema{
+			MapProperties:    []string{"title"},
+			LayerProperties:  []string{"z"},
+			ObjectProperties: map[string][]string{"door": {"key"}},
+		}, []IssueKind{MissingProperty, MissingProperty, MissingProperty}},
+		{"missing class property", validateTMX("orthogonal", "",
+			testTileset+validateLayer+`<objectgroup id="2" name="objects">
+  <object id="1" name="door" class="door" x="0" y="0"/>
+ </objectgroup>`), &Schema{
+			ObjectProperties: map[string][]string{"door": {"key"}},
+		}, []IssueKind{MissingProperty}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues, err := Validate(writeTestMap(t, test.tmx), test.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != len(test.kinds) {
+				t.Fatalf("issues: %v, expected %d issues", issues,
+					len(test.kinds))
+			}
+			for i, issue := range issues {
+				if issue.Kind != test.kinds[i] {
+					t.Errorf("issue %d: %s: kind: %d, expected: %d", i,
+						issue, issue.Kind, test.kinds[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMissingFile(t *testing.T) {
+	_, err := Validate("missing.tmx", nil)
+	if err == nil {
+		t.Errorf("no error for missing file")
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	issues, err := Validate("res/map.tmx", nil, WithFS(testFS(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) > 0 {
+		t.Errorf("issues of map from file system: %v", issues)
+	}
+	loader := ImageLoaderFunc(func(path string, r io.Reader) (image.Image, error) {
+		return image.NewRGBA(image.Rect(0, 0, 96, 32)), nil
+	})
+	path := writeTestMap(t, gridTMX("..", ".#"))
+	issues, err = Validate(path, nil, WithImageLoader(loader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Kind != TilesetSizeMismatch {
+		t.Errorf("issues of map with image loader: %v, expected size mismatch",
+			issues)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * wang.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Wang set with colors on tile corners.
+	WangCorner = "corner"
+	// Wang set with colors on tile edges.
+	WangEdge = "edge"
+	// Wang set with colors on tile corners and edges.
+	WangMixed = "mixed"
+)
+
+// Struct for tileset Wang set.
+type WangSet struct {
+	name    string
+	tileset string
+	setType string
+	colors  []string
+	tiles   []wangTile
+}
+
+// Struct for Wang set tile.
+type wangTile struct {
+	id int
+	// Colors of tile edges and corners, clockwise
+	// starting from the top edge.
+	wangID [8]int
+}
+
+// Struct for autotiler that picks layer tiles
+// from Wang set.
+type Autotiler struct {
+	wangSet *WangSet
+	rand    *rand.Rand
+}
+
+// newWangSet creates new Wang set for tileset with specified name.
+func newWangSet(tileset string, tmxSet tmxWangSet) (*WangSet, error) {
+	ws := new(WangSet)
+	ws.name = tmxSet.Name
+	ws.tileset = tileset
+	ws.setType = tmxSet.Type
+	for _, c := range tmxSet.Colors {
+		ws.colors = append(ws.colors, c.Name)
+	}
+	for _, t := range tmxSet.Tiles {
+		wangID, err := parseWangID(t.WangID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse Wang ID: %d: %w",
+				t.TileID, err)
+		}
+		ws.tiles = append(ws.tiles, wangTile{int(t.TileID), wangID})
+	}
+	return ws, nil
+}
+
+// Name returns Wang set name.
+func (ws *WangSet) Name() string {
+	return ws.name
+}
+
+// Tileset returns name of Wang set tileset.
+func (ws *WangSet) Tileset() string {
+	return ws.tileset
+}
+
+// Type returns Wang set type: corner, edge or mixed.
+func (ws *WangSet) Type() string {
+	return ws.setType
+}
+
+// Colors returns names of Wang set colors. Color with
+// index 0 in this slice has index 1 in the Wang set,
+// index 0 in the set means no color.
+func (ws *WangSet) Colors() []string {
+	return ws.colors
+}
+
+// usesIndex checks if Wang ID index is used by Wang set type.
+// Even indices are edges and odd indices are corners.
+func (ws *WangSet) usesIndex(i int) bool {
+	switch ws.setType {
+	case WangCorner:
+		return i%2 == 1
+	case WangEdge:
+		return i%2 == 0
+	default:
+		return true
+	}
+}
+
+// NewAutotiler creates new autotiler for specified Wang set.
+func NewAutotiler(ws *WangSet) *Autotiler {
+	a := new(Autotiler)
+	a.wangSet = ws
+	return a
+}
+
+// SetRand sets random source used to pick one of many tiles
+// that are matching the terrain. If no source is set then
+// the first matching tile is always used.
+func (a *Autotiler) SetRand(r *rand.Rand) {
+	a.rand = r
+}
+
+// Apply sets tiles in all cells of specified layer that have
+// terrain color assigned in specified terrain grid. Terrain
+// grid contains Wang set color indices of all map cells,
+// accessed by terrain[y][x]. Cells with color 0 are skipped.
+// Colors are assigned to cells, so tiles edges and corners
+// shared with cells of other color are set to the color with
+// higher index.
+func (a *Autotiler) Apply(l *Layer, terrain [][]int) error {
+	for y := range terrain {
+		for x := range terrain[y] {
+			err := a.applyCell(l, terrain, x, y)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyCell sets tiles in layer cell with specified grid
+// coordinates and all cells around it, after terrain color
+// of this cell was changed in specified terrain grid.
+func (a *Autotiler) ApplyCell(l *Layer, terrain [][]int, x, y int) error {
+	for cy := y - 1; cy <= y+1; cy++ {
+		for cx := x - 1; cx <= x+1; cx++ {
+			err := a.applyCell(l, terrain, cx, cy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyCell sets tile matching specified terrain in layer cell
+// with specified grid coordinates.
+func (a *Autotiler) applyCell(l *Layer, terrain [][]int, x, y int) error {
+	color := terrainColor(terrain, x, y)
+	if color < 1 {
+		return nil
+	}
+	wangID := cellWangID(terrain, x, y)
+	tileID, ok := a.matchTile(wangID)
+	if !ok {
+		return fmt.Errorf("no matching tile for cell: %d, %d", x, y)
+	}
+	return l.SetTile(x, y, a.wangSet.tileset, tileID)
+}
+
+// matchTile returns ID of Wang set tile that is the best
+// match for specified Wang ID. False is returned if Wang
+// set has no tiles.
+func (a *Autotiler) matchTile(wangID [8]int) (int, bool) {
+	best := -1
+	matches := make([]int, 0)
+	for _, t := range a.wangSet.tiles {
+		score := 0
+		for i := range wangID {
+			if !a.wangSet.usesIndex(i) || t.wangID[i] == wangID[i] {
+				score++
+			}
+		}
+		if score > best {
+			best = score
+			matches = matches[:0]
+		}
+		if score == best {
+			matches = append(matches, t.id)
+		}
+	}
+	if len(matches) < 1 {
+		return 0, false
+	}
+	if a.rand == nil {
+		return matches[0], true
+	}
+	return matches[a.rand.Intn(len(matches))], true
+}
+
+// cellWangID returns Wang ID for cell with specified
+// coordinates in specified terrain grid.
+func cellWangID(terrain [][]int, x, y int) [8]int {
+	// Cells offsets for each edge and corner, clockwise
+	// starting from the top edge.
+	offsets := [8][][2]int{
+		{{0, -1}},
+		{{0, -1}, {1, -1}, {1, 0}},
+		{{1, 0}},
+		{{1, 0}, {1, 1}, {0, 1}},
+		{{0, 1}},
+		{{0, 1}, {-1, 1}, {-1, 0}},
+		{{-1, 0}},
+		{{-1, 0}, {-1, -1}, {0, -1}},
+	}
+	var wangID [8]int
+	color := terrainColor(terrain, x, y)
+	for i, cells := range offsets {
+		wangID[i] = color
+		for _, c := range cells {
+			cellColor := terrainColor(terrain, x+c[0], y+c[1])
+			if cellColor > wangID[i] {
+				wangID[i] = cellColor
+			}
+		}
+	}
+	return wangID
+}
+
+// terrainColor returns color of terrain grid cell with
+// specified coordinates, or 0 if cell is outside the grid.
+func terrainColor(terrain [][]int, x, y int) int {
+	if y < 0 || y >= len(terrain) || x < 0 || x >= len(terrain[y]) {
+		return 0
+	}
+	return terrain[y][x]
+}
+
+// parseWangID parses specified TMX Wang ID. Supports both
+// comma-separated format and legacy hexadecimal format.
+func parseWangID(s string) (wangID [8]int, err error) {
+	if strings.HasPrefix(s, "0x") {
+		val, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil {
+			return wangID, err
+		}
+		for i := range wangID {
+			wangID[i] = int(val >> (4 * i) & 0xF)
+		}
+		return wangID, nil
+	}
+	values := strings.Split(s, ",")
+	if len(values) != len(wangID) {
+		return wangID, fmt.Errorf("invalid number of values: %d", len(values))
+	}
+	for i, v := range values {
+		wangID[i], err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return wangID, err
+		}
+	}
+	return wangID, nil
+}
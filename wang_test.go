@@ -0,0 +1,138 @@
+/*
+ * wang_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"testing"
+)
+
+// wangTMX is TMX data of map with corner Wang set, with
+// tiles: 0 - all corners of the first color, 1 - all corners
+// of the second color, 2 - right corners of the second color,
+// 3 - left corners of the second color.
+const wangTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="3" height="1" tilewidth="32" tileheight="32">
+ <tileset firstgid="1" name="tiles" tilewidth="32" tileheight="32" tilecount="4" columns="4">
+  <image source="tiles.png" width="128" height="32"/>
+  <wangsets>
+   <wangset name="terrain" type="corner" tile="-1">
+    <wangcolor name="grass" color="#00ff00" tile="-1" probability="1"/>
+    <wangcolor name="sand" color="#ffff00" tile="-1" probability="1"/>
+    <wangtile tileid="0" wangid="0,1,0,1,0,1,0,1"/>
+    <wangtile tileid="1" wangid="0,2,0,2,0,2,0,2"/>
+    <wangtile tileid="2" wangid="0,2,0,2,0,1,0,1"/>
+    <wangtile tileid="3" wangid="0x20201010"/>
+   </wangset>
+  </wangsets>
+ </tileset>
+ <layer id="1" name="ground" width="3" height="1">
+  <data encoding="csv">0,0,0</data>
+ </layer>
+</map>`
+
+func TestParseWangID(t *testing.T) {
+	tests := []struct {
+		in     string
+		wangID [8]int
+		err    bool
+	}{
+		{"0,1,0,2,0,3,0,4", [8]int{0, 1, 0, 2, 0, 3, 0, 4}, false},
+		{"1, 2, 3, 4, 5, 6, 7, 8", [8]int{1, 2, 3, 4, 5, 6, 7, 8}, false},
+		{"0x87654321", [8]int{1, 2, 3, 4, 5, 6, 7, 8}, false},
+		{"1,2,3", [8]int{}, true},
+		{"1,2,3,4,5,6,7,x", [8]int{}, true},
+		{"0xzz", [8]int{}, true},
+	}
+	for _, test := range tests {
+		wangID, err := parseWangID(test.in)
+		if (err != nil) != test.err {
+			t.Errorf("%s: error: %v, expected error: %v", test.in, err,
+				test.err)
+			continue
+		}
+		if !test.err && wangID != test.wangID {
+			t.Errorf("%s: Wang ID: %v, expected: %v", test.in, wangID,
+				test.wangID)
+		}
+	}
+}
+
+func TestCellWangID(t *testing.T) {
+	tests := []struct {
+		name    string
+		terrain [][]int
+		x, y    int
+		wangID  [8]int
+	}{
+		{"single cell", [][]int{{1}}, 0, 0, [8]int{1, 1, 1, 1, 1, 1, 1, 1}},
+		{"higher right", [][]int{{1, 2}}, 0, 0, [8]int{1, 2, 2, 2, 1, 1, 1, 1}},
+		{"lower left", [][]int{{1, 2}}, 1, 0, [8]int{2, 2, 2, 2, 2, 2, 2, 2}},
+		{"higher diagonal", [][]int{{1, 0}, {0, 3}}, 0, 0,
+			[8]int{1, 1, 1, 3, 1, 1, 1, 1}},
+		{"conflicting neighbours", [][]int{
+			{0, 2, 0},
+			{3, 1, 0},
+			{0, 0, 0},
+		}, 1, 1, [8]int{2, 2, 1, 1, 1, 3, 3, 3}},
+	}
+	for _, test := range tests {
+		wangID := cellWangID(test.terrain, test.x, test.y)
+		if wangID != test.wangID {
+			t.Errorf("%s: Wang ID: %v, expected: %v", test.name, wangID,
+				test.wangID)
+		}
+	}
+}
+
+func TestAutotilerApply(t *testing.T) {
+	m, err := NewMap(writeTestMap(t, wangTMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := m.WangSet("terrain")
+	if ws == nil {
+		t.Fatal("no Wang set")
+	}
+	if len(ws.Colors()) != 2 {
+		t.Fatalf("Wang set colors: %v, expected 2 colors", ws.Colors())
+	}
+	l := m.Layers()[0]
+	err = NewAutotiler(ws).Apply(l, [][]int{{1, 2, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Higher color index wins on shared corners, so cell
+	// with the second color stays whole and both cells
+	// around it get its color on adjacent corners.
+	for x, id := range []int{2, 1, 3} {
+		tile := l.TileAt(x, 0)
+		if tile == nil {
+			t.Errorf("cell %d, 0: no tile", x)
+			continue
+		}
+		if tileID := int(tile.Frame().Min.X / 32); tileID != id {
+			t.Errorf("cell %d, 0: tile: %d, expected: %d", x, tileID, id)
+		}
+	}
+}
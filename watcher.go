@@ -0,0 +1,141 @@
+/*
+ * watcher.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Struct for watcher that reloads map after changes
+// in the map file or tileset images. Watcher polls files
+// modification times, so it does not require any
+// external service.
+type Watcher struct {
+	m         *Map
+	interval  time.Duration
+	lastCheck time.Time
+	files     map[string]fileState
+	onReload  func(err error)
+}
+
+// Struct for state of watched file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher creates new watcher for specified map, that
+// checks map files with specified interval.
+func NewWatcher(m *Map, interval time.Duration) *Watcher {
+	w := new(Watcher)
+	w.m = m
+	w.interval = interval
+	w.lastCheck = time.Now()
+	w.files = w.fileStates()
+	return w
+}
+
+// SetOnReload sets function triggered after each map reload
+// attempt, with error if reload failed.
+func (w *Watcher) SetOnReload(f func(err error)) {
+	w.onReload = f
+}
+
+// Update checks map files for changes if watcher interval
+// elapsed since the last check and reloads the map if any
+// file was changed. Should be called regularly, e.g. on each
+// frame, on the same thread that draws the map.
+func (w *Watcher) Update() {
+	if time.Since(w.lastCheck) < w.interval {
+		return
+	}
+	w.lastCheck = time.Now()
+	w.Check()
+}
+
+// Check checks map files for changes immediately and reloads
+// the map if any file was changed. Returns true if map was
+// reloaded.
+func (w *Watcher) Check() bool {
+	files := w.fileStates()
+	changed := make([]string, 0)
+	for path, state := range w.files {
+		if files[path] != state {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) < 1 {
+		return false
+	}
+	if w.m.cache != nil {
+		for _, path := range changed {
+			w.m.cache.invalidate(w.m.cacheKey(path))
+		}
+	}
+	err := w.m.Reload()
+	// Files are updated even on failure, to not reload
+	// the map until the next change, e.g. when the map
+	// file was saved only partially.
+	w.files = w.fileStates()
+	if w.onReload != nil {
+		w.onReload(err)
+	}
+	return err == nil
+}
+
+// fileStates returns states of the map file and all image
+// files of tilesets and image layers, missing files have
+// zero state.
+func (w *Watcher) fileStates() map[string]fileState {
+	files := make(map[string]fileState)
+	paths := []string{w.m.path}
+	for _, ts := range w.m.tilesets {
+		paths = append(paths, ts.imagePaths()...)
+	}
+	for _, l := range w.m.layers {
+		if len(l.imagePath) > 0 {
+			paths = append(paths, l.imagePath)
+		}
+	}
+	for _, path := range paths {
+		info, err := w.m.stat(path)
+		if err != nil {
+			files[path] = fileState{}
+			continue
+		}
+		files[path] = fileState{info.ModTime(), info.Size()}
+	}
+	return files
+}
+
+// stat returns info about file with specified path, from
+// map file system or OS file system.
+func (m *Map) stat(path string) (fs.FileInfo, error) {
+	if m.opts.fsys == nil {
+		return os.Stat(path)
+	}
+	return fs.Stat(m.opts.fsys, path)
+}
@@ -0,0 +1,192 @@
+/*
+ * watcher_test.go
+ *
+ * Copyright 2026 Dariusz Sikora <ds@isangeles.dev>
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston,
+ * MA 02110-1301, USA.
+ *
+ *
+ */
+
+package stone
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopxl/pixel"
+)
+
+// changeFile writes specified data to file with specified
+// path and moves file modification time forward, so the change
+// is detected regardless of file system time resolution.
+func changeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime := info.ModTime().Add(time.Second)
+	err = os.Chtimes(path, modTime, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reloadCounter returns function for watcher reloads that
+// counts reloads and records the last reload error.
+func reloadCounter(count *int, lastErr *error) func(err error) {
+	return func(err error) {
+		*count++
+		*lastErr = err
+	}
+}
+
+func TestWatcherMapChanged(t *testing.T) {
+	path := writeTestMap(t, gridTMX("..", ".."))
+	m, err := NewMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fog := NewFog(m)
+	fog.states[0] = Explored
+	m.SetFog(fog)
+	w := NewWatcher(m, time.Second)
+	reloads := 0
+	var reloadErr error
+	w.SetOnReload(reloadCounter(&reloads, &reloadErr))
+	if w.Check() {
+		t.Errorf("map reloaded without changes")
+	}
+	changeFile(t, path, []byte(gridTMX("...", "...", "..#")))
+	if !w.Check() {
+		t.Fatalf("map not reloaded after change")
+	}
+	if reloads != 1 || reloadErr != nil {
+		t.Errorf("reloads: %d, error: %v, expected: 1, nil", reloads, reloadErr)
+	}
+	if m.Bounds() != pixel.R(0, 0, 96, 96) {
+		t.Errorf("map bounds: %v, expected: %v", m.Bounds(), pixel.R(0, 0, 96, 96))
+	}
+	if !m.Blocked(2, 2) {
+		t.Errorf("new map cell not loaded")
+	}
+	if m.Fog() != fog {
+		t.Fatalf("map fog replaced on reload")
+	}
+	if len(fog.states) != 9 || fog.width != 3 {
+		t.Errorf("fog size: %d, width: %d, expected: 9, 3", len(fog.states),
+			fog.width)
+	}
+	if fog.State(0, 0) != Explored || fog.State(2, 2) != Unexplored {
+		t.Errorf("fog states: %v, %v, expected: %v, %v", fog.State(0, 0),
+			fog.State(2, 2), Explored, Unexplored)
+	}
+	if w.Check() {
+		t.Errorf("map reloaded again without changes")
+	}
+}
+
+func TestWatcherImageChanged(t *testing.T) {
+	cache := NewResourceCache()
+	path := writeTestMap(t, gridTMX("..", ".."))
+	m, err := NewMap(path, WithResourceCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgPath := filepath.Join(filepath.Dir(path), "tiles.png")
+	old := m.cached[0]
+	oldPic := m.Tilesets()[0].Picture()
+	w := NewWatcher(m, time.Second)
+	redPath := filepath.Join(t.TempDir(), "red.png")
+	writeTestImage(t, redPath, 128, 32, color.RGBA{R: 255, A: 255})
+	changeFile(t, imgPath, mustReadFile(t, redPath))
+	if !w.Check() {
+		t.Fatalf("map not reloaded after image change")
+	}
+	pic, ok := m.Tilesets()[0].Picture().(*pixel.PictureData)
+	if !ok || pic == oldPic {
+		t.Fatalf("tileset picture not reloaded")
+	}
+	if c := pic.Color(pixel.V(1, 1)); c != pixel.RGB(1, 0, 0) {
+		t.Errorf("tileset picture color: %v, expected: %v", c, pixel.RGB(1, 0, 0))
+	}
+	if old.refs != 0 {
+		t.Errorf("old picture references: %d, expected: 0", old.refs)
+	}
+	if cache.Len() != 1 || cache.References(imgPath) != 1 {
+		t.Errorf("cache: pictures: %d, references: %d, expected: 1, 1",
+			cache.Len(), cache.References(imgPath))
+	}
+	m.Release()
+	if cache.Len() != 0 {
+		t.Errorf("cache not empty after release: %d", cache.Len())
+	}
+}
+
+func TestWatcherPartialWrite(t *testing.T) {
+	path := writeTestMap(t, gridTMX("..", ".."))
+	m, err := NewMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(m, time.Second)
+	reloads := 0
+	var reloadErr error
+	w.SetOnReload(reloadCounter(&reloads, &reloadErr))
+	tmx := gridTMX("...", "...")
+	changeFile(t, path, []byte(tmx[:len(tmx)/2]))
+	if w.Check() {
+		t.Errorf("map reloaded from partially written file")
+	}
+	if reloads != 1 || reloadErr == nil {
+		t.Errorf("reloads: %d, error: %v, expected: 1, reload error", reloads,
+			reloadErr)
+	}
+	if m.Bounds() != pixel.R(0, 0, 64, 64) {
+		t.Errorf("map changed after failed reload: %v", m.Bounds())
+	}
+	if w.Check() || reloads != 1 {
+		t.Errorf("reload retriggered without changes: reloads: %d", reloads)
+	}
+	changeFile(t, path, []byte(tmx))
+	if !w.Check() {
+		t.Fatalf("map not reloaded after the next change")
+	}
+	if reloads != 2 || reloadErr != nil {
+		t.Errorf("reloads: %d, error: %v, expected: 2, nil", reloads, reloadErr)
+	}
+	if m.Bounds() != pixel.R(0, 0, 96, 64) {
+		t.Errorf("map bounds: %v, expected: %v", m.Bounds(), pixel.R(0, 0, 96, 64))
+	}
+}
+
+// mustReadFile returns content of file with specified path.
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}